@@ -7,6 +7,7 @@ import (
 
 	"gachimatsu-backend/internal/api"
 	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/jobs"
 	"gachimatsu-backend/internal/middleware"
 
 	"github.com/gorilla/mux"
@@ -19,6 +20,9 @@ func main() {
 	}
 	defer database.Close()
 
+	// 定期実行ジョブを開始
+	jobs.Start()
+
 	// ポート番号を環境変数から取得、デフォルトは8080
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -31,6 +35,7 @@ func main() {
 	// ミドルウェアを適用
 	router.Use(middleware.CORS)
 	router.Use(middleware.Logger)
+	router.Use(middleware.CurrentUser)
 
 	// APIルートを設定
 	apiRouter := router.PathPrefix("/api/v1").Subrouter()
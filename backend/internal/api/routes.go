@@ -2,27 +2,117 @@ package api
 
 import (
 	"gachimatsu-backend/internal/handlers"
+	"gachimatsu-backend/internal/middleware"
 
 	"github.com/gorilla/mux"
 )
 
 // SetupRoutes APIルートを設定
 func SetupRoutes(router *mux.Router) {
+	// 認証関連のエンドポイント
+	router.HandleFunc("/auth/login", handlers.Login).Methods("POST")
+	router.HandleFunc("/auth/logout", handlers.Logout).Methods("POST")
+	router.HandleFunc("/auth/verify-email", handlers.RequestEmailVerification).Methods("POST")
+	router.HandleFunc("/auth/verify-email/confirm", handlers.ConfirmEmailVerification).Methods("POST")
+	router.HandleFunc("/auth/password-reset", handlers.RequestPasswordReset).Methods("POST")
+	router.HandleFunc("/auth/password-reset/confirm", handlers.ConfirmPasswordReset).Methods("POST")
+
 	// メニュー関連のエンドポイント
 	router.HandleFunc("/menus", handlers.GetMenus).Methods("GET")
 	router.HandleFunc("/menus/{id}", handlers.GetMenu).Methods("GET")
+	router.HandleFunc("/menus/{id}/rank-history", handlers.GetMenuRankHistory).Methods("GET")
+	router.HandleFunc("/menus/{id}/similar", handlers.GetSimilarMenus).Methods("GET")
+	router.HandleFunc("/menus/{id}/price-history", handlers.GetMenuPriceHistory).Methods("GET")
 
 	// ユーザー関連のエンドポイント
 	router.HandleFunc("/users", handlers.GetUsers).Methods("GET")
 	router.HandleFunc("/users/emails", handlers.GetUserEmails).Methods("GET")
 	router.HandleFunc("/users/{id}", handlers.GetUserByID).Methods("GET")
 	router.HandleFunc("/users/{id}", handlers.DeleteUser).Methods("DELETE")
+	router.HandleFunc("/users/{id}/recommendations", handlers.GetUserRecommendations).Methods("GET")
+	router.HandleFunc("/users/{id}/time-zone", handlers.UpdateUserTimeZone).Methods("PUT")
+	router.HandleFunc("/users/{id}/settings", handlers.GetUserSettings).Methods("GET")
+	router.HandleFunc("/users/{id}/settings", handlers.UpdateUserSettings).Methods("PUT")
+	router.HandleFunc("/users/{id}/stats", handlers.GetUserStats).Methods("GET")
+	router.HandleFunc("/users/{id}/calendar", handlers.GetUserCalendar).Methods("GET")
+	router.HandleFunc("/users/{id}/year/{year}", handlers.GetUserYearInReview).Methods("GET")
+	router.HandleFunc("/users/{id}/year/{year}/card.svg", handlers.GetUserYearInReviewCard).Methods("GET")
+	router.HandleFunc("/users/{id}/orders", handlers.GetUserOrders).Methods("GET")
+	router.HandleFunc("/users/{id}/orders", handlers.CreateUserOrder).Methods("POST")
+	router.HandleFunc("/users/{id}/ratings", handlers.RateMenu).Methods("POST")
+	router.HandleFunc("/users/{id}/achievements", handlers.GetUserAchievements).Methods("GET")
+	router.HandleFunc("/users/{id}/followers", handlers.GetFollowers).Methods("GET")
+	router.HandleFunc("/users/{id}/following", handlers.GetFollowing).Methods("GET")
+	router.HandleFunc("/users/{id}/following", handlers.FollowUser).Methods("POST")
+	router.HandleFunc("/users/{id}/following/{followee_id}", handlers.UnfollowUser).Methods("DELETE")
+	router.HandleFunc("/users/{id}/feed", handlers.GetUserFeed).Methods("GET")
+	router.HandleFunc("/users/{id}/notifications", handlers.GetUserNotifications).Methods("GET")
+	router.HandleFunc("/users/{id}/notifications/unread-count", handlers.GetUnreadNotificationCount).Methods("GET")
+	router.HandleFunc("/users/{id}/notifications/read-all", handlers.MarkAllNotificationsRead).Methods("PUT")
+	router.HandleFunc("/users/{id}/notifications/{notification_id}/read", handlers.MarkNotificationRead).Methods("PUT")
+	router.HandleFunc("/users/{id}/notification-preferences", handlers.GetNotificationPreferences).Methods("GET")
+	router.HandleFunc("/users/{id}/notification-preferences", handlers.UpdateNotificationPreferences).Methods("PUT")
+	router.HandleFunc("/users/{id}/spending", handlers.GetUserSpending).Methods("GET")
+	router.HandleFunc("/users/{id}/budget", handlers.GetUserBudget).Methods("GET")
+	router.HandleFunc("/users/{id}/budget", handlers.UpdateUserBudget).Methods("PUT")
+	router.HandleFunc("/users/{id}/digest/preview", handlers.PreviewWeeklyDigest).Methods("GET")
+	router.HandleFunc("/users/{id}/progress", handlers.GetUserProgress).Methods("GET")
+	router.HandleFunc("/users/{id}/share-links", handlers.GetShareLinks).Methods("GET")
+	router.HandleFunc("/users/{id}/share-links", handlers.CreateShareLink).Methods("POST")
+	router.HandleFunc("/users/{id}/share-links/{link_id}", handlers.RevokeShareLink).Methods("DELETE")
+
+	// 食事記録へのコメント・リアクションのエンドポイント（呼び出し元はセッショントークンで指定）
+	router.HandleFunc("/orders/{id}/comments", handlers.GetOrderComments).Methods("GET")
+	router.HandleFunc("/orders/{id}/comments", handlers.CreateOrderComment).Methods("POST")
+	router.HandleFunc("/orders/{id}/comments/{comment_id}", handlers.UpdateOrderComment).Methods("PUT")
+	router.HandleFunc("/orders/{id}/comments/{comment_id}", handlers.DeleteOrderComment).Methods("DELETE")
+	router.HandleFunc("/orders/{id}/reactions", handlers.GetOrderReactions).Methods("GET")
+	router.HandleFunc("/orders/{id}/reactions/{emoji}", handlers.AddOrderReaction).Methods("PUT")
+	router.HandleFunc("/orders/{id}/reactions/{emoji}", handlers.RemoveOrderReaction).Methods("DELETE")
 
 	// 統計情報のエンドポイント
 	router.HandleFunc("/stats", handlers.GetStats).Methods("GET")
+	router.HandleFunc("/stats/price-changes", handlers.GetPriceChangeReport).Methods("GET")
 
 	// 人気メニューランキング関連のエンドポイント
 	router.HandleFunc("/ranking/popular", handlers.GetPopularMenus).Methods("GET")
 	router.HandleFunc("/ranking/popular/{category}", handlers.GetPopularMenusByCategory).Methods("GET")
 	router.HandleFunc("/ranking/menu-ranking", handlers.GetMenuRanking).Methods("GET")
+	router.HandleFunc("/ranking/trending", handlers.GetTrendingMenus).Methods("GET")
+
+	// ユーザーリーダーボードのエンドポイント
+	router.HandleFunc("/leaderboards/{type}", handlers.GetLeaderboard).Methods("GET")
+
+	// グループ関連のエンドポイント（呼び出し元はセッショントークンで指定）
+	router.HandleFunc("/groups", handlers.GetMyGroups).Methods("GET")
+	router.HandleFunc("/groups", handlers.CreateGroup).Methods("POST")
+	router.HandleFunc("/groups/join", handlers.JoinGroup).Methods("POST")
+	router.HandleFunc("/groups/{id}", handlers.GetGroup).Methods("GET")
+	router.HandleFunc("/groups/{id}/invite-code", handlers.RegenerateGroupInviteCode).Methods("POST")
+	router.HandleFunc("/groups/{id}/members", handlers.GetGroupMembers).Methods("GET")
+	router.HandleFunc("/groups/{id}/members/{user_id}", handlers.RemoveGroupMember).Methods("DELETE")
+	router.HandleFunc("/groups/{id}/progress", handlers.GetGroupProgress).Methods("GET")
+	router.HandleFunc("/groups/{id}/menu-ranking", handlers.GetGroupMenuRanking).Methods("GET")
+	router.HandleFunc("/groups/{id}/leaderboards/{type}", handlers.GetGroupLeaderboard).Methods("GET")
+	router.HandleFunc("/groups/{id}/feed", handlers.GetGroupFeed).Methods("GET")
+
+	// チャレンジ関連のエンドポイント
+	router.HandleFunc("/challenges", handlers.GetChallenges).Methods("GET")
+	router.HandleFunc("/challenges/{id}", handlers.GetChallenge).Methods("GET")
+	router.HandleFunc("/challenges/{id}/participants", handlers.JoinChallenge).Methods("POST")
+	router.HandleFunc("/challenges/{id}/participants/{user_id}", handlers.GetChallengeProgress).Methods("GET")
+	router.HandleFunc("/challenges/{id}/leaderboard", handlers.GetChallengeLeaderboard).Methods("GET")
+
+	// 共有リンクの公開エンドポイント（ログイン不要）
+	router.HandleFunc("/shared/{token}", handlers.GetSharedContent).Methods("GET")
+	router.HandleFunc("/shared/{token}/og.png", handlers.GetSharedImage).Methods("GET")
+
+	// 埋め込み用バッジのエンドポイント（ログイン不要）
+	router.HandleFunc("/badges/users/{id}/progress.svg", handlers.GetProgressBadge).Methods("GET")
+
+	// 管理者向けのエンドポイント
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(middleware.AdminAuth)
+	adminRouter.HandleFunc("/menus/{id}", handlers.UpdateMenu).Methods("PUT")
+	adminRouter.HandleFunc("/challenges", handlers.CreateChallenge).Methods("POST")
 }
@@ -0,0 +1,89 @@
+// Package auth パスワードのハッシュ化とトークンの生成・検証
+package auth
+
+import (
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// パスワードのハッシュ化の設定（PBKDF2-HMAC-SHA256）
+const (
+	passwordAlgorithm  = "pbkdf2-sha256"
+	passwordIterations = 600000
+	passwordSaltLength = 16
+	passwordKeyLength  = 32
+)
+
+// MinPasswordLength パスワードの最小文字数
+const MinPasswordLength = 8
+
+// ErrWeakPassword パスワードが短すぎる場合のエラー
+var ErrWeakPassword = fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+
+// HashPassword パスワードをハッシュ化
+// 結果は「アルゴリズム$反復回数$ソルト$ハッシュ」の形式
+func HashPassword(password string) (string, error) {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return "", ErrWeakPassword
+	}
+
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, passwordIterations, passwordKeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		passwordAlgorithm,
+		strconv.Itoa(passwordIterations),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// CheckPassword パスワードがハッシュと一致するか判定
+func CheckPassword(hash, password string) (bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordAlgorithm {
+		return false, errors.New("unsupported password hash")
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations <= 0 {
+		return false, errors.New("invalid password hash")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false, errors.New("invalid password hash")
+	}
+	expected, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false, errors.New("invalid password hash")
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, len(expected))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
+
+// dummyPasswordSalt CheckDummyPasswordで使う固定のソルト
+var dummyPasswordSalt = make([]byte, passwordSaltLength)
+
+// CheckDummyPassword CheckPasswordと同じ計算だけを行う（結果は使わない）
+// ユーザーが存在しない場合やパスワード未設定の場合にも同じ時間をかけ、
+// 応答時間からアカウントの有無を推測されないようにする
+func CheckDummyPassword(password string) {
+	pbkdf2.Key(sha256.New, password, dummyPasswordSalt, passwordIterations, passwordKeyLength)
+}
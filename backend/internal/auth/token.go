@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// トークンの用途
+const (
+	PurposeVerifyEmail   = "verify_email"
+	PurposePasswordReset = "password_reset"
+	PurposeShareLink     = "share_link"
+)
+
+// ErrInvalidToken トークンが不正か期限切れの場合のエラー
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// TokenClaims 署名付きトークンに含める情報
+type TokenClaims struct {
+	Purpose string
+	UserID  int
+	// TokenID 使用済みの判定に使うトークンのID
+	TokenID int
+	// ExpiresAt 有効期限（ゼロ値の場合は期限なし）
+	ExpiresAt time.Time
+}
+
+var (
+	secretOnce sync.Once
+	secret     []byte
+	// secretConfigured 鍵が環境変数で設定されているか
+	secretConfigured bool
+)
+
+// tokenSecret 環境変数AUTH_TOKEN_SECRETから署名の鍵を取得
+// 未設定の場合は起動ごとにランダムな鍵を使う（再起動すると発行済みのトークンは無効になる）
+func tokenSecret() []byte {
+	secretOnce.Do(func() {
+		if value := os.Getenv("AUTH_TOKEN_SECRET"); value != "" {
+			secret = []byte(value)
+			secretConfigured = true
+			return
+		}
+		log.Println("AUTH_TOKEN_SECRET is not set; using a random key for this process")
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	})
+	return secret
+}
+
+// HasConfiguredSecret 署名の鍵が環境変数AUTH_TOKEN_SECRETで設定されているかを返す
+// 未設定の場合は再起動で発行済みのトークンが無効になるため、長く使うトークンは発行しない
+func HasConfiguredSecret() bool {
+	tokenSecret()
+	return secretConfigured
+}
+
+// sign ペイロードのHMAC-SHA256署名を作成
+func sign(payload string) []byte {
+	mac := hmac.New(sha256.New, tokenSecret())
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// SignToken 署名付きトークンを作成
+func SignToken(claims TokenClaims) string {
+	var expiresAt int64
+	if !claims.ExpiresAt.IsZero() {
+		expiresAt = claims.ExpiresAt.Unix()
+	}
+	payload := fmt.Sprintf("%s|%d|%d|%d", claims.Purpose, claims.UserID, claims.TokenID, expiresAt)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(sign(payload))
+}
+
+// VerifyToken 署名付きトークンの署名・用途・有効期限を検証して情報を取得
+// 使用済みかどうかは呼び出し側でTokenIDを使って確認する
+func VerifyToken(token, purpose string, now time.Time) (*TokenClaims, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, sign(string(payload))) {
+		return nil, ErrInvalidToken
+	}
+
+	parts := strings.Split(string(payload), "|")
+	if len(parts) != 4 || parts[0] != purpose {
+		return nil, ErrInvalidToken
+	}
+	userID, err1 := strconv.Atoi(parts[1])
+	tokenID, err2 := strconv.Atoi(parts[2])
+	expiresAt, err3 := strconv.ParseInt(parts[3], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return nil, ErrInvalidToken
+	}
+
+	claims := &TokenClaims{
+		Purpose: purpose,
+		UserID:  userID,
+		TokenID: tokenID,
+	}
+	if expiresAt != 0 {
+		claims.ExpiresAt = time.Unix(expiresAt, 0)
+		if !now.Before(claims.ExpiresAt) {
+			return nil, ErrInvalidToken
+		}
+	}
+	return claims, nil
+}
+
+// NewSessionToken ランダムなセッショントークンを生成
+func NewSessionToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// HashSessionToken セッショントークンをデータベースに保存する形式に変換
+// トークン自体は保存せず、SHA-256のハッシュだけを保存する
+func HashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+// Package cache 有効期限と件数の上限があるメモリ上のキャッシュ
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache 有効期限と件数の上限があるキャッシュ
+// 保持する値は共有されるため、呼び出し側で変更してはならない
+type Cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]entry
+}
+
+type entry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// New 最大maxEntries件を保持するキャッシュを作成
+func New(maxEntries int) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]entry),
+	}
+}
+
+// Get キャッシュから有効期限内の値を取得
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set キャッシュに値をttlの間保存（ttlが0以下の場合はキャッシュしない）
+// 上限に達している場合は期限切れの値を、それでも空きが無い場合は最も早く期限が切れる値を破棄する
+func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 || c.maxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			} else if oldestKey == "" || e.expiresAt.Before(oldest) {
+				oldestKey, oldest = k, e.expiresAt
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			delete(c.entries, oldestKey)
+		}
+	}
+	c.entries[key] = entry{value: value, expiresAt: now.Add(ttl)}
+}
+
+// Clear キャッシュをすべて破棄
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]entry)
+}
+
+// Len 保持している値の件数（期限切れで未破棄の値を含む）
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
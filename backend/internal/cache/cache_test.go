@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCacheGetSet(t *testing.T) {
+	c := New(10)
+	c.Set("a", 1, time.Minute)
+
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v; want 1, true", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) found a value that was never set")
+	}
+
+	c.Set("expired", 2, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := c.Get("expired"); ok {
+		t.Error("Get(expired) returned an expired value")
+	}
+
+	c.Set("disabled", 3, 0)
+	if _, ok := c.Get("disabled"); ok {
+		t.Error("a value with ttl 0 was cached")
+	}
+
+	c.Clear()
+	if n := c.Len(); n != 0 {
+		t.Errorf("Len() after Clear = %d, want 0", n)
+	}
+}
+
+func TestCacheEvictsWhenFull(t *testing.T) {
+	c := New(3)
+	c.Set("first", 1, time.Minute)
+	c.Set("second", 2, 2*time.Minute)
+	c.Set("third", 3, 3*time.Minute)
+
+	// 上限に達したら最も早く期限が切れる値を破棄する
+	c.Set("fourth", 4, 4*time.Minute)
+	if n := c.Len(); n != 3 {
+		t.Fatalf("Len() = %d, want 3", n)
+	}
+	if _, ok := c.Get("first"); ok {
+		t.Error("the entry expiring first was not evicted")
+	}
+	for _, key := range []string{"second", "third", "fourth"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("Get(%s) not found", key)
+		}
+	}
+
+	// 既存のキーの更新では破棄しない
+	c.Set("second", 20, time.Minute)
+	if n := c.Len(); n != 3 {
+		t.Errorf("Len() after update = %d, want 3", n)
+	}
+}
+
+func TestCacheDropsExpiredEntriesWhenFull(t *testing.T) {
+	c := New(100)
+	for i := 0; i < 100; i++ {
+		c.Set(fmt.Sprint(i), i, time.Nanosecond)
+	}
+	time.Sleep(time.Millisecond)
+
+	c.Set("fresh", 1, time.Minute)
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1 after expired entries are dropped", n)
+	}
+}
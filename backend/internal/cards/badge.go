@@ -0,0 +1,88 @@
+package cards
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// バッジの文字の大きさと余白（shields.ioのflatスタイルに合わせる）
+const (
+	badgeHeight   = 20
+	badgePadding  = 6
+	badgeFontSize = 11
+)
+
+// badgeColor 制覇率に応じたバッジの色
+func badgeColor(percentage float64) string {
+	switch {
+	case percentage >= 100:
+		return "#4c1"
+	case percentage >= 75:
+		return "#97ca00"
+	case percentage >= 50:
+		return "#dfb317"
+	case percentage >= 25:
+		return "#fe7d37"
+	default:
+		return "#e05d44"
+	}
+}
+
+// badgeTextWidth バッジの文字列のおおよその幅（フォントを読み込まずに文字の種類から見積もる）
+func badgeTextWidth(s string) int {
+	width := 0.0
+	for _, r := range s {
+		switch {
+		case r >= utf8.RuneSelf:
+			width += badgeFontSize
+		case r == ' ' || r == '.' || r == ',' || r == '(' || r == ')' || r == '/' || r == 'i' || r == 'l':
+			width += 4
+		case r == '%' || r == 'm' || r == 'w' || (r >= 'A' && r <= 'Z'):
+			width += 8.5
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
+
+// maxBadgeLabelLength バッジの左側の文字列の最大文字数
+const maxBadgeLabelLength = 30
+
+// ProgressBadgeSVG 制覇状況をshields.io風のSVGバッジにする
+// labelは左側の文字列、右側には制覇率と制覇数を表示する
+func ProgressBadgeSVG(label string, percentage float64, conquered, total int) []byte {
+	label = truncate(label, maxBadgeLabelLength)
+	value := fmt.Sprintf("%.1f%% (%d/%d)", percentage, conquered, total)
+	labelWidth := badgeTextWidth(label) + badgePadding*2
+	valueWidth := badgeTextWidth(value) + badgePadding*2
+	width := labelWidth + valueWidth
+	title := escape(label + ": " + value)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" role="img" aria-label="%s">`, width, badgeHeight, title)
+	fmt.Fprintf(&buf, `<title>%s</title>`, title)
+	buf.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(&buf, `<clipPath id="r"><rect width="%d" height="%d" rx="3" fill="#fff"/></clipPath>`, width, badgeHeight)
+	buf.WriteString(`<g clip-path="url(#r)">`)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#555"/>`, labelWidth, badgeHeight)
+	fmt.Fprintf(&buf, `<rect x="%d" width="%d" height="%d" fill="%s"/>`, labelWidth, valueWidth, badgeHeight, badgeColor(percentage))
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="url(#s)"/>`, width, badgeHeight)
+	buf.WriteString(`</g>`)
+	fmt.Fprintf(&buf, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="%d">`, badgeFontSize)
+	for _, part := range []struct {
+		x    int
+		text string
+	}{
+		{labelWidth / 2, label},
+		{labelWidth + valueWidth/2, value},
+	} {
+		text := escape(part.text)
+		fmt.Fprintf(&buf, `<text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`, part.x, text)
+		fmt.Fprintf(&buf, `<text x="%d" y="14">%s</text>`, part.x, text)
+	}
+	buf.WriteString(`</g></svg>`)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,52 @@
+// Package cards SNSなどで共有するための画像を作成する
+package cards
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// 対応している言語
+const (
+	LanguageJapanese = "ja"
+	LanguageEnglish  = "en"
+)
+
+// normalizeLanguage 言語の指定を対応している言語に変換（en以外は日本語）
+func normalizeLanguage(lang string) string {
+	if strings.HasPrefix(strings.ToLower(lang), LanguageEnglish) {
+		return LanguageEnglish
+	}
+	return LanguageJapanese
+}
+
+// escape SVGに埋め込む文字列をエスケープ
+func escape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// formatNumber 数値を3桁ごとにカンマで区切った文字列に変換
+func formatNumber(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// truncate 文字列を最大n文字に切り詰める（切り詰めた場合は末尾に…を付ける）
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
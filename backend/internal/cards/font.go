@@ -0,0 +1,69 @@
+package cards
+
+import (
+	_ "embed"
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// mplusRegular PNG画像の文字に使うM+ 1pフォント（日本語を含む、ライセンスはfonts/LICENSE）
+//
+//go:embed fonts/mplus-1p-regular.ttf
+var mplusRegular []byte
+
+var pngFont = mustParseFont(mplusRegular)
+
+// mustParseFont フォントを読み込む（埋め込んだフォントが壊れている場合はpanic）
+func mustParseFont(data []byte) *opentype.Font {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// newFace 指定した大きさ（ピクセル）のフォントフェイスを作成
+// フォントフェイスは並行して使えないため、画像を描くたびに作成する
+func newFace(size float64) font.Face {
+	face, err := opentype.NewFace(pngFont, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+// drawText 文字列を(x, y)を左上として描く
+func drawText(dst *image.RGBA, x, y int, face font.Face, c color.Color, s string) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y+face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(s)
+}
+
+// fitText 文字列を幅width（ピクセル）に収まるように切り詰める（切り詰めた場合は末尾に…を付ける）
+func fitText(face font.Face, s string, width int) string {
+	s = strings.TrimSpace(s)
+	limit := fixed.I(width)
+	if font.MeasureString(face, s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	for n := len(runes) - 1; n > 0; n-- {
+		if t := string(runes[:n]) + "…"; font.MeasureString(face, t) <= limit {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,227 @@
+package cards
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"gachimatsu-backend/internal/cache"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// 共有リンクのOGP画像の大きさ
+const (
+	shareImageWidth  = 1200
+	shareImageHeight = 630
+)
+
+// 共有リンクのOGP画像の配色
+var (
+	shareBackground = color.RGBA{0xff, 0xf8, 0xe7, 0xff}
+	shareHeader     = color.RGBA{0xf6, 0xad, 0x00, 0xff}
+	shareText       = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	shareSubText    = color.RGBA{0x77, 0x77, 0x77, 0xff}
+	shareAccent     = color.RGBA{0xc0, 0x39, 0x2b, 0xff}
+	shareTrack      = color.RGBA{0xea, 0xdc, 0xc0, 0xff}
+)
+
+// 画像の取得の制限
+const (
+	imageFetchTimeout = 5 * time.Second
+	maxImageBytes     = 5 << 20
+	maxImagePixels    = 4096 * 4096
+)
+
+// shareThumbnailRect OGP画像のメニューの写真（または円グラフ）を描く位置
+var shareThumbnailRect = image.Rect(60, 150, 480, 570)
+
+// キャッシュの有効期限と件数の上限
+// 描画したOGP画像は載せる内容ごとに、メニューの写真は縮小したものをURLごとに保持する
+const (
+	shareImageCacheTTL   = 10 * time.Minute
+	thumbnailCacheTTL    = time.Hour
+	thumbnailFailureTTL  = 5 * time.Minute
+	maxCachedShareImages = 500
+	maxCachedThumbnails  = 200
+)
+
+var (
+	shareImageCache = cache.New(maxCachedShareImages)
+	thumbnailCache  = cache.New(maxCachedThumbnails)
+)
+
+// ShareCard 共有リンクのOGP画像に載せる内容
+type ShareCard struct {
+	// Title メニュー名やユーザー名（長い場合は切り詰める）
+	Title      string
+	Percentage float64
+	Conquered  int
+	Total      int
+	// ThumbnailURL メニューの写真のURL（空か取得できない場合は制覇率の円グラフを描く）
+	ThumbnailURL string
+}
+
+// cacheKey OGP画像のキャッシュのキー（載せる内容が変わると別のキーになる）
+func (card ShareCard) cacheKey() string {
+	return fmt.Sprintf("%s|%.1f|%d|%d|%s", card.Title, card.Percentage, card.Conquered, card.Total, card.ThumbnailURL)
+}
+
+// ShareImagePNG 共有リンクのOGP画像をPNGで作成
+// 同じ内容の画像はキャッシュしたものを返す
+func ShareImagePNG(card ShareCard) ([]byte, error) {
+	key := card.cacheKey()
+	if cached, ok := shareImageCache.Get(key); ok {
+		return cached.([]byte), nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, renderShareImage(card)); err != nil {
+		return nil, err
+	}
+	shareImageCache.Set(key, buf.Bytes(), shareImageCacheTTL)
+	return buf.Bytes(), nil
+}
+
+// renderShareImage 共有リンクのOGP画像を描く
+func renderShareImage(card ShareCard) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, shareImageWidth, shareImageHeight))
+	fillRect(img, img.Bounds(), shareBackground)
+	fillRect(img, image.Rect(0, 0, shareImageWidth, 90), shareHeader)
+	drawText(img, 60, 18, newFace(44), color.White, "がちまつ")
+
+	// 左側: メニューの写真か制覇率の円グラフ
+	if thumbnail := cachedThumbnail(card.ThumbnailURL); thumbnail != nil {
+		draw.Draw(img, shareThumbnailRect, thumbnail, image.Point{}, draw.Over)
+	} else {
+		drawRing(img, shareThumbnailRect, card.Percentage/100)
+	}
+
+	// 右側: タイトル・制覇率・制覇数
+	x := 540
+	titleFace := newFace(52)
+	drawText(img, x, 140, titleFace, shareText, fitText(titleFace, card.Title, shareImageWidth-x-60))
+	drawText(img, x, 220, newFace(128), shareAccent, fmt.Sprintf("%.1f%%", card.Percentage))
+	drawText(img, x, 390, newFace(40), shareSubText, fmt.Sprintf("制覇 %d / %d メニュー", card.Conquered, card.Total))
+
+	bar := image.Rect(x, 460, shareImageWidth-60, 500)
+	fillRect(img, bar, shareTrack)
+	filled := bar
+	filled.Max.X = bar.Min.X + int(float64(bar.Dx())*math.Min(math.Max(card.Percentage/100, 0), 1))
+	fillRect(img, filled, shareAccent)
+
+	return img
+}
+
+// fillRect 矩形を単色で塗りつぶす
+func fillRect(dst *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(dst, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawRing rの中に進捗（0〜1）を表す円グラフを描く（12時の位置から時計回り）
+func drawRing(dst *image.RGBA, r image.Rectangle, progress float64) {
+	cx := float64(r.Min.X+r.Max.X) / 2
+	cy := float64(r.Min.Y+r.Max.Y) / 2
+	outer := math.Min(float64(r.Dx()), float64(r.Dy())) / 2
+	inner := outer * 0.7
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			dx := float64(x) + 0.5 - cx
+			dy := float64(y) + 0.5 - cy
+			d := math.Hypot(dx, dy)
+			if d > outer || d < inner {
+				continue
+			}
+			angle := math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			if angle < progress*2*math.Pi {
+				dst.Set(x, y, shareAccent)
+			} else {
+				dst.Set(x, y, shareTrack)
+			}
+		}
+	}
+}
+
+// cachedThumbnail URLのメニューの写真を取得し、OGP画像に載せる大きさに縮小したものを返す
+// 取得した写真はキャッシュし、取得に失敗した場合もしばらくは再取得せずnilを返す
+func cachedThumbnail(url string) image.Image {
+	if url == "" {
+		return nil
+	}
+	if cached, ok := thumbnailCache.Get(url); ok {
+		thumbnail, _ := cached.(image.Image)
+		return thumbnail
+	}
+
+	src, err := fetchImage(url)
+	if err != nil {
+		log.Printf("Failed to fetch image %s: %v", url, err)
+		thumbnailCache.Set(url, nil, thumbnailFailureTTL)
+		return nil
+	}
+	thumbnail := image.NewRGBA(image.Rect(0, 0, shareThumbnailRect.Dx(), shareThumbnailRect.Dy()))
+	drawCover(thumbnail, thumbnail.Bounds(), src)
+	thumbnailCache.Set(url, image.Image(thumbnail), thumbnailCacheTTL)
+	return thumbnail
+}
+
+// drawCover srcを縦横比を保ったままrを覆うように縮小し、はみ出した部分を切り取って描く
+func drawCover(dst *image.RGBA, r image.Rectangle, src image.Image) {
+	b := src.Bounds()
+	if b.Empty() {
+		return
+	}
+	scale := math.Max(float64(r.Dx())/float64(b.Dx()), float64(r.Dy())/float64(b.Dy()))
+	w := int(math.Round(float64(r.Dx()) / scale))
+	h := int(math.Round(float64(r.Dy()) / scale))
+	crop := image.Rect(0, 0, w, h).Add(b.Min).Add(image.Pt((b.Dx()-w)/2, (b.Dy()-h)/2)).Intersect(b)
+	xdraw.CatmullRom.Scale(dst, r, src, crop, draw.Over, nil)
+}
+
+// fetchImage URLの画像を取得してデコード（http/httpsのJPEG・PNG・GIFのみ）
+func fetchImage(url string) (image.Image, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, errors.New("unsupported image url")
+	}
+
+	client := &http.Client{Timeout: imageFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch image: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, err
+	}
+	// 小さなファイルでも画素数が極端に多い画像はデコードしない
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.New("image is too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -0,0 +1,131 @@
+package cards
+
+import (
+	"bytes"
+	"fmt"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// 1年のまとめのカードの大きさ（OGP画像の推奨サイズ）
+const (
+	yearInReviewWidth  = 1200
+	yearInReviewHeight = 630
+)
+
+// yearInReviewLabels 1年のまとめのカードに表示する文言
+type yearInReviewLabels struct {
+	title      string
+	visits     string
+	spend      string
+	streak     string
+	conquered  string
+	topMenus   string
+	category   string
+	timeOfDay  string
+	percentile string
+	noVisits   string
+	timesOfDay map[string]string
+}
+
+var yearInReviewText = map[string]yearInReviewLabels{
+	LanguageJapanese: {
+		title:      "%sさんの%d年の松屋",
+		visits:     "来店 %s回",
+		spend:      "支出 %s円",
+		streak:     "最長 %d日連続",
+		conquered:  "新メニュー %d品",
+		topMenus:   "よく食べたメニュー",
+		category:   "好きなカテゴリ: %s",
+		timeOfDay:  "よく行く時間帯: %s",
+		percentile: "%.0f%%のユーザーより多く来店",
+		noVisits:   "この年の来店記録はありません",
+		timesOfDay: map[string]string{
+			models.TimeOfDayMorning:   "朝",
+			models.TimeOfDayLunch:     "昼",
+			models.TimeOfDayAfternoon: "午後",
+			models.TimeOfDayDinner:    "夜",
+			models.TimeOfDayLateNight: "深夜",
+		},
+	},
+	LanguageEnglish: {
+		title:      "%s's %d at Matsuya",
+		visits:     "%s visits",
+		spend:      "%s yen spent",
+		streak:     "%d-day best streak",
+		conquered:  "%d new menus",
+		topMenus:   "Top menus",
+		category:   "Favourite category: %s",
+		timeOfDay:  "Usual time: %s",
+		percentile: "More visits than %.0f%% of users",
+		noVisits:   "No visits recorded this year",
+		timesOfDay: map[string]string{
+			models.TimeOfDayMorning:   "Morning",
+			models.TimeOfDayLunch:     "Lunch",
+			models.TimeOfDayAfternoon: "Afternoon",
+			models.TimeOfDayDinner:    "Dinner",
+			models.TimeOfDayLateNight: "Late night",
+		},
+	},
+}
+
+// YearInReviewSVG 1年のまとめを共有用のSVG画像にする
+// langは表示する言語（対応していない場合は日本語）
+func YearInReviewSVG(review *models.YearInReview, lang string) []byte {
+	text := yearInReviewText[normalizeLanguage(lang)]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="'Hiragino Sans', 'Noto Sans JP', sans-serif">`,
+		yearInReviewWidth, yearInReviewHeight, yearInReviewWidth, yearInReviewHeight)
+	buf.WriteString(`<rect width="100%" height="100%" fill="#fff8e7"/>`)
+	buf.WriteString(`<rect width="100%" height="120" fill="#f6ad00"/>`)
+	fmt.Fprintf(&buf, `<text x="60" y="80" font-size="48" font-weight="bold" fill="#ffffff">%s</text>`,
+		escape(fmt.Sprintf(text.title, truncate(review.UserName, 20), review.Year)))
+
+	if review.TotalVisits == 0 {
+		fmt.Fprintf(&buf, `<text x="60" y="340" font-size="40" fill="#555555">%s</text>`, escape(text.noVisits))
+		buf.WriteString(`</svg>`)
+		return buf.Bytes()
+	}
+
+	// 主な数値
+	stats := []string{
+		fmt.Sprintf(text.visits, formatNumber(review.TotalVisits)),
+		fmt.Sprintf(text.spend, formatNumber(review.TotalSpend)),
+		fmt.Sprintf(text.streak, review.LongestStreak),
+		fmt.Sprintf(text.conquered, review.MenusConquered),
+	}
+	for i, stat := range stats {
+		x := 60 + (i%2)*540
+		y := 200 + (i/2)*70
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" font-size="44" font-weight="bold" fill="#333333">%s</text>`, x, y, escape(stat))
+	}
+
+	// よく食べたメニュー
+	fmt.Fprintf(&buf, `<text x="60" y="360" font-size="28" fill="#c0392b">%s</text>`, escape(text.topMenus))
+	for i, menu := range review.TopMenus {
+		if i == 3 {
+			break
+		}
+		fmt.Fprintf(&buf, `<text x="60" y="%d" font-size="30" fill="#333333">%d. %s ×%d</text>`,
+			405+i*42, i+1, escape(truncate(menu.Name, 20)), menu.Count)
+	}
+
+	// カテゴリ・時間帯・他のユーザーとの比較
+	var details []string
+	if review.TopCategory != nil {
+		details = append(details, fmt.Sprintf(text.category, truncate(review.TopCategory.Category, 12)))
+	}
+	if label, ok := text.timesOfDay[review.MostCommonTimeOfDay]; ok {
+		details = append(details, fmt.Sprintf(text.timeOfDay, label))
+	}
+	if review.ActiveUsers > 1 {
+		details = append(details, fmt.Sprintf(text.percentile, review.Percentile))
+	}
+	for i, detail := range details {
+		fmt.Fprintf(&buf, `<text x="640" y="%d" font-size="28" fill="#555555">%s</text>`, 405+i*42, escape(detail))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
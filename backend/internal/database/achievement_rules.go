@@ -0,0 +1,172 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// achievementContext 実績の判定に使うユーザーの活動
+type achievementContext struct {
+	visits     []visit
+	ratedMenus int
+	loc        *time.Location
+	// menusByCategory カテゴリごとの全メニューID
+	menusByCategory map[string][]int
+	// eatenMenus ユーザーが注文したことのあるメニューID
+	eatenMenus map[int]bool
+}
+
+// achievementRule 実績の獲得条件
+// progressは現在の進捗を、targetは獲得に必要な値を返す
+type achievementRule struct {
+	code        string
+	name        string
+	description string
+	target      func(ctx *achievementContext) int
+	progress    func(ctx *achievementContext) int
+}
+
+// fixedTarget 獲得に必要な値が固定の条件
+func fixedTarget(n int) func(ctx *achievementContext) int {
+	return func(ctx *achievementContext) int { return n }
+}
+
+// staticAchievementRules メニュー構成によらない実績の獲得条件
+var staticAchievementRules = []achievementRule{
+	{
+		code:        "first_visit",
+		name:        "はじめての松屋",
+		description: "初めて食事を記録する",
+		target:      fixedTarget(1),
+		progress:    func(ctx *achievementContext) int { return len(ctx.visits) },
+	},
+	{
+		code:        "first_breakfast",
+		name:        "朝定食デビュー",
+		description: "朝（5時〜10時）に初めて食事を記録する",
+		target:      fixedTarget(1),
+		progress: func(ctx *achievementContext) int {
+			for _, v := range ctx.visits {
+				if timeOfDay(v.At.In(ctx.loc)) == models.TimeOfDayMorning {
+					return 1
+				}
+			}
+			return 0
+		},
+	},
+	{
+		code:        "monthly_visits_10",
+		name:        "常連さん",
+		description: "同じ月に10回来店する",
+		target:      fixedTarget(10),
+		progress: func(ctx *achievementContext) int {
+			counts := make(map[time.Time]int)
+			best := 0
+			for _, v := range ctx.visits {
+				month := period.Month(v.At, ctx.loc).From
+				counts[month]++
+				if counts[month] > best {
+					best = counts[month]
+				}
+			}
+			return best
+		},
+	},
+	{
+		code:        "day_streak_7",
+		name:        "毎日松屋",
+		description: "7日連続で来店する",
+		target:      fixedTarget(7),
+		progress: func(ctx *achievementContext) int {
+			visited := make(map[time.Time]bool)
+			for _, v := range ctx.visits {
+				visited[period.Day(v.At, ctx.loc).From] = true
+			}
+			_, longest := streaks(visited, period.Day(time.Now(), ctx.loc).From, func(t time.Time, n int) time.Time {
+				return t.AddDate(0, 0, n)
+			})
+			return longest
+		},
+	},
+	conqueredMenusRule(10),
+	conqueredMenusRule(50),
+	conqueredMenusRule(100),
+	ratedMenusRule(10),
+	ratedMenusRule(50),
+}
+
+// conqueredMenusRule n種類のメニューを制覇する実績
+func conqueredMenusRule(n int) achievementRule {
+	return achievementRule{
+		code:        fmt.Sprintf("conquered_menus_%d", n),
+		name:        fmt.Sprintf("%dメニュー制覇", n),
+		description: fmt.Sprintf("%d種類のメニューを食べる", n),
+		target:      fixedTarget(n),
+		progress:    func(ctx *achievementContext) int { return len(ctx.eatenMenus) },
+	}
+}
+
+// ratedMenusRule n種類のメニューを評価する実績
+func ratedMenusRule(n int) achievementRule {
+	return achievementRule{
+		code:        fmt.Sprintf("rated_menus_%d", n),
+		name:        fmt.Sprintf("%dメニュー評価", n),
+		description: fmt.Sprintf("%d種類のメニューを評価する", n),
+		target:      fixedTarget(n),
+		progress:    func(ctx *achievementContext) int { return ctx.ratedMenus },
+	}
+}
+
+// categoryCompleteRule カテゴリの全メニューを制覇する実績
+func categoryCompleteRule(category string) achievementRule {
+	return achievementRule{
+		code:        "category_complete:" + category,
+		name:        category + "制覇",
+		description: fmt.Sprintf("「%s」カテゴリの全メニューを食べる", category),
+		target:      func(ctx *achievementContext) int { return len(ctx.menusByCategory[category]) },
+		progress: func(ctx *achievementContext) int {
+			count := 0
+			for _, menuID := range ctx.menusByCategory[category] {
+				if ctx.eatenMenus[menuID] {
+					count++
+				}
+			}
+			return count
+		},
+	}
+}
+
+// achievementRules 全実績の獲得条件を取得
+// カテゴリ制覇の実績は現在のメニュー構成のカテゴリごとに作られる
+func achievementRules(ctx *achievementContext) []achievementRule {
+	rules := append([]achievementRule{}, staticAchievementRules...)
+
+	categories := make([]string, 0, len(ctx.menusByCategory))
+	for category := range ctx.menusByCategory {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		rules = append(rules, categoryCompleteRule(category))
+	}
+
+	return rules
+}
+
+// achievementName 実績コードから実績名を取得
+func achievementName(code string) string {
+	for _, rule := range staticAchievementRules {
+		if rule.code == code {
+			return rule.name
+		}
+	}
+	if category, ok := strings.CutPrefix(code, "category_complete:"); ok {
+		return categoryCompleteRule(category).name
+	}
+	return code
+}
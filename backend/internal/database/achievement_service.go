@@ -0,0 +1,209 @@
+package database
+
+import (
+	"time"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// achievementRecords 実績の判定に使うユーザーの記録
+type achievementRecords struct {
+	loc     *time.Location
+	orders  []userOrder
+	catalog map[int]menuInfo
+	// ratingVisibilities 評価したメニューごとの評価の公開範囲
+	ratingVisibilities []string
+}
+
+// loadAchievementRecords 実績の判定に使うユーザーの記録を取得
+func loadAchievementRecords(userID int) (*achievementRecords, error) {
+	loc, err := getUserLocation(userID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := getUserOrders(userID)
+	if err != nil {
+		return nil, err
+	}
+	ratingVisibilities, err := getRatingVisibilities(userID)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return &achievementRecords{loc: loc, orders: orders, catalog: catalog, ratingVisibilities: ratingVisibilities}, nil
+}
+
+// isVisibleWithin 公開範囲visibilityの記録が、公開範囲levelの相手にも見えるか
+func isVisibleWithin(visibility, level string) bool {
+	switch level {
+	case models.VisibilityPublic:
+		return visibility == models.VisibilityPublic
+	case models.VisibilityFollowers:
+		return visibility == models.VisibilityPublic || visibility == models.VisibilityFollowers
+	}
+	return true
+}
+
+// newAchievementContext 公開範囲levelの相手にも見える記録だけで実績の判定に使う活動を作成
+// levelがprivateの場合はすべての記録を使う
+func newAchievementContext(records *achievementRecords, level string) *achievementContext {
+	var orders []userOrder
+	for _, order := range records.orders {
+		if isVisibleWithin(order.Visibility, level) {
+			orders = append(orders, order)
+		}
+	}
+	ratedMenus := 0
+	for _, visibility := range records.ratingVisibilities {
+		if isVisibleWithin(visibility, level) {
+			ratedMenus++
+		}
+	}
+
+	ctx := &achievementContext{
+		visits:          groupVisits(orders),
+		ratedMenus:      ratedMenus,
+		loc:             records.loc,
+		menusByCategory: make(map[string][]int),
+		eatenMenus:      make(map[int]bool),
+	}
+	for _, menu := range records.catalog {
+		ctx.menusByCategory[menu.Category] = append(ctx.menusByCategory[menu.Category], menu.ID)
+	}
+	for _, order := range orders {
+		ctx.eatenMenus[order.MenuID] = true
+	}
+
+	return ctx
+}
+
+// achievementVisibility 実績の公開範囲を、獲得に必要な記録の公開範囲から決める
+// 公開の記録だけで達成していればpublic、フォロワー限定の記録までで達成していればfollowers、それ以外はprivate
+func achievementVisibility(records *achievementRecords, code string) string {
+	for _, level := range []string{models.VisibilityPublic, models.VisibilityFollowers} {
+		ctx := newAchievementContext(records, level)
+		for _, rule := range achievementRules(ctx) {
+			if rule.code != code {
+				continue
+			}
+			if target := rule.target(ctx); target > 0 && rule.progress(ctx) >= target {
+				return level
+			}
+		}
+	}
+	return models.VisibilityPrivate
+}
+
+// getUnlockedAchievements ユーザーの獲得済み実績の獲得日時を取得
+func getUnlockedAchievements(userID int) (map[string]time.Time, error) {
+	rows, err := DB.Query("SELECT achievement_code, unlocked_at FROM user_achievements WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	unlocked := make(map[string]time.Time)
+	for rows.Next() {
+		var code string
+		var unlockedAt time.Time
+		if err := rows.Scan(&code, &unlockedAt); err != nil {
+			return nil, err
+		}
+		unlocked[code] = unlockedAt
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return unlocked, nil
+}
+
+// GetUserAchievements ユーザーの全実績の獲得状況と進捗を取得
+func GetUserAchievements(userID int) ([]models.Achievement, error) {
+	records, err := loadAchievementRecords(userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx := newAchievementContext(records, models.VisibilityPrivate)
+	unlocked, err := getUnlockedAchievements(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var achievements []models.Achievement
+	for _, rule := range achievementRules(ctx) {
+		achievement := models.Achievement{
+			Code:        rule.code,
+			Name:        rule.name,
+			Description: rule.description,
+			Progress:    rule.progress(ctx),
+			Target:      rule.target(ctx),
+		}
+		if achievement.Progress > achievement.Target {
+			achievement.Progress = achievement.Target
+		}
+		if unlockedAt, ok := unlocked[rule.code]; ok {
+			achievement.Unlocked = true
+			achievement.UnlockedAt = &unlockedAt
+			achievement.Progress = achievement.Target
+		}
+		achievements = append(achievements, achievement)
+	}
+
+	return achievements, nil
+}
+
+// EvaluateAchievements ユーザーの実績の獲得条件を判定し、新たに達成した実績を記録
+// 食事記録や評価を保存したときに呼び出し、新たに獲得した実績を返す
+// 実績は獲得に必要な記録の公開範囲で記録し、非公開の記録に基づく実績は他のユーザーに見せない
+func EvaluateAchievements(userID int) ([]models.Achievement, error) {
+	records, err := loadAchievementRecords(userID)
+	if err != nil {
+		return nil, err
+	}
+	ctx := newAchievementContext(records, models.VisibilityPrivate)
+	unlocked, err := getUnlockedAchievements(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var newlyUnlocked []models.Achievement
+	for _, rule := range achievementRules(ctx) {
+		if _, ok := unlocked[rule.code]; ok {
+			continue
+		}
+		target := rule.target(ctx)
+		if target <= 0 || rule.progress(ctx) < target {
+			continue
+		}
+
+		unlockedAt := time.Now()
+		result, err := DB.Exec(
+			"INSERT IGNORE INTO user_achievements (user_id, achievement_code, unlocked_at, visibility) VALUES (?, ?, ?, ?)",
+			userID, rule.code, unlockedAt, achievementVisibility(records, rule.code),
+		)
+		if err != nil {
+			return nil, err
+		}
+		// 同時に別のリクエストで獲得済みになった場合は含めない
+		if rowsAffected, err := result.RowsAffected(); err != nil || rowsAffected == 0 {
+			continue
+		}
+
+		newlyUnlocked = append(newlyUnlocked, models.Achievement{
+			Code:        rule.code,
+			Name:        rule.name,
+			Description: rule.description,
+			Unlocked:    true,
+			UnlockedAt:  &unlockedAt,
+			Progress:    target,
+			Target:      target,
+		})
+	}
+
+	return newlyUnlocked, nil
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GetPasswordHash ユーザーのパスワードのハッシュを取得（未設定の場合は空文字）
+func GetPasswordHash(userID int) (string, error) {
+	var hash sql.NullString
+	if err := DB.QueryRow("SELECT password_hash FROM users WHERE id = ?", userID).Scan(&hash); err != nil {
+		return "", err
+	}
+	return hash.String, nil
+}
+
+// CreateSession ログインセッションを作成
+func CreateSession(userID int, tokenHash string, expiresAt time.Time) error {
+	_, err := DB.Exec(
+		"INSERT INTO sessions (user_id, token_hash, created_at, expires_at) VALUES (?, ?, NOW(), ?)",
+		userID, tokenHash, expiresAt,
+	)
+	return err
+}
+
+// GetSessionUserID 有効なセッションのユーザーIDを取得
+// セッションが存在しないか、期限切れ・無効化済みの場合はsql.ErrNoRowsを返す
+func GetSessionUserID(tokenHash string) (int, error) {
+	var userID int
+	err := DB.QueryRow(
+		"SELECT user_id FROM sessions WHERE token_hash = ? AND revoked_at IS NULL AND expires_at > NOW()",
+		tokenHash,
+	).Scan(&userID)
+	return userID, err
+}
+
+// RevokeSession セッションを無効化（ログアウト）
+func RevokeSession(tokenHash string) error {
+	_, err := DB.Exec("UPDATE sessions SET revoked_at = NOW() WHERE token_hash = ? AND revoked_at IS NULL", tokenHash)
+	return err
+}
+
+// CreateUserToken メールアドレス確認・パスワード再設定のトークンを発行し、IDを返す
+func CreateUserToken(userID int, purpose string, expiresAt time.Time) (int, error) {
+	result, err := DB.Exec(
+		"INSERT INTO user_tokens (user_id, purpose, expires_at, created_at) VALUES (?, ?, ?, NOW())",
+		userID, purpose, expiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	id, err := result.LastInsertId()
+	return int(id), err
+}
+
+// useUserToken トークンを使用済みにする
+// トークンが存在しないか、使用済み・期限切れの場合はsql.ErrNoRowsを返す
+func useUserToken(tx *sql.Tx, tokenID, userID int, purpose string) error {
+	result, err := tx.Exec(
+		`UPDATE user_tokens SET used_at = NOW() 
+		WHERE id = ? AND user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > NOW()`,
+		tokenID, userID, purpose,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// VerifyUserEmail トークンを使用済みにしてメールアドレスを確認済みにする
+// トークンが使用済み・期限切れの場合はsql.ErrNoRowsを返す
+func VerifyUserEmail(userID, tokenID int, purpose string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := useUserToken(tx, tokenID, userID, purpose); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE users SET email_verified_at = NOW() WHERE id = ? AND email_verified_at IS NULL", userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ResetPassword トークンを使用済みにしてパスワードを再設定
+// 未使用の他の再設定トークンも使えなくし、既存のセッションはすべて無効化する
+// トークンが使用済み・期限切れの場合はsql.ErrNoRowsを返す
+func ResetPassword(userID, tokenID int, purpose, passwordHash string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := useUserToken(tx, tokenID, userID, purpose); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE users SET password_hash = ? WHERE id = ?", passwordHash, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(
+		"UPDATE user_tokens SET used_at = NOW() WHERE user_id = ? AND purpose = ? AND used_at IS NULL",
+		userID, purpose,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE sessions SET revoked_at = NOW() WHERE user_id = ? AND revoked_at IS NULL", userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AllowAuthRequest 確認メール・再設定メールのリクエストが回数制限内か判定し、制限内なら記録する
+// keyの直近windowの間のリクエストがlimit回以上の場合はfalseを返す
+func AllowAuthRequest(action, key string, limit int, window time.Duration) (bool, error) {
+	since := time.Now().Add(-window)
+
+	// 制限の判定に使わなくなった古い履歴は削除する
+	if _, err := DB.Exec(
+		"DELETE FROM auth_request_log WHERE action = ? AND key_value = ? AND created_at < ?",
+		action, key, since,
+	); err != nil {
+		return false, err
+	}
+
+	var count int
+	err := DB.QueryRow(
+		"SELECT COUNT(*) FROM auth_request_log WHERE action = ? AND key_value = ? AND created_at >= ?",
+		action, key, since,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	if count >= limit {
+		return false, nil
+	}
+
+	_, err = DB.Exec(
+		"INSERT INTO auth_request_log (action, key_value, created_at) VALUES (?, ?, NOW())",
+		action, key,
+	)
+	return err == nil, err
+}
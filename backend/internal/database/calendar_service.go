@@ -0,0 +1,92 @@
+package database
+
+import (
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// GetVisitCalendar ユーザーの1年間の来店カレンダーと連続記録を取得
+// 日付はlocのタイムゾーンで区切り、同じ日の複数の来店は連続記録では1日として数える
+// 連続記録は指定年に限らず全期間の注文から計算し、viewerIDのユーザーから見える注文だけを数える
+func GetVisitCalendar(userID, viewerID int, year int, loc *time.Location) (*models.VisitCalendar, error) {
+	orders, err := getVisibleUserOrders(userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &models.VisitCalendar{
+		UserID:   userID,
+		Year:     year,
+		TimeZone: loc.String(),
+		Days:     []models.CalendarDay{},
+	}
+
+	// 日ごとの来店回数を集計
+	visitsByDay := make(map[time.Time]int)
+	for _, v := range groupVisits(orders) {
+		visitsByDay[period.Day(v.At, loc).From]++
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		visits := visitsByDay[day]
+		if visits > 0 {
+			calendar.TotalVisitDays++
+		}
+		calendar.Days = append(calendar.Days, models.CalendarDay{
+			Date:   day.Format(period.DateLayout),
+			Visits: visits,
+		})
+	}
+
+	// 日単位・週単位の連続記録を計算
+	visitedWeeks := make(map[time.Time]bool)
+	visitedDays := make(map[time.Time]bool)
+	for day := range visitsByDay {
+		visitedDays[day] = true
+		visitedWeeks[period.Week(day, loc).From] = true
+	}
+	now := time.Now()
+	calendar.CurrentDayStreak, calendar.LongestDayStreak = streaks(visitedDays, period.Day(now, loc).From, func(t time.Time, n int) time.Time {
+		return t.AddDate(0, 0, n)
+	})
+	calendar.CurrentWeekStreak, calendar.LongestWeekStreak = streaks(visitedWeeks, period.Week(now, loc).From, func(t time.Time, n int) time.Time {
+		return t.AddDate(0, 0, 7*n)
+	})
+
+	return calendar, nil
+}
+
+// streaks 来店のあった日（または週）の集合から現在の連続記録と最長の連続記録を計算
+// currentは今日（今週）の開始時刻、stepはn日（週）後の開始時刻を返す関数
+func streaks(visited map[time.Time]bool, current time.Time, step func(time.Time, int) time.Time) (int, int) {
+	longest := 0
+	for start := range visited {
+		// 連続の先頭からだけ数える
+		if visited[step(start, -1)] {
+			continue
+		}
+		length := 0
+		for t := start; visited[t]; t = step(t, 1) {
+			length++
+		}
+		if length > longest {
+			longest = length
+		}
+	}
+
+	// 今日（今週）まだ来店していなければ昨日（先週）から数える
+	t := current
+	if !visited[t] {
+		t = step(t, -1)
+	}
+	currentStreak := 0
+	for ; visited[t]; t = step(t, -1) {
+		currentStreak++
+	}
+
+	return currentStreak, longest
+}
@@ -0,0 +1,398 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// チャレンジ操作のエラー
+var (
+	ErrChallengeEnded = errors.New("challenge has ended")
+	ErrAlreadyJoined  = errors.New("already joined the challenge")
+)
+
+// CreateChallenge チャレンジを作成
+func CreateChallenge(req models.CreateChallengeRequest) (*models.Challenge, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO challenges (title, description, starts_at, ends_at, rule, required_count, created_at) 
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
+	`
+	result, err := tx.Exec(query, req.Title, req.Description, req.StartsAt, req.EndsAt, req.Rule, req.RequiredCount)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, menuID := range req.MenuIDs {
+		if _, err := tx.Exec("INSERT INTO challenge_targets (challenge_id, menu_id) VALUES (?, ?)", id, menuID); err != nil {
+			return nil, err
+		}
+	}
+	for _, category := range req.Categories {
+		if _, err := tx.Exec("INSERT INTO challenge_targets (challenge_id, category) VALUES (?, ?)", id, category); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return GetChallengeByID(int(id))
+}
+
+// challengeColumns チャレンジ取得時の共通のSELECT句
+const challengeColumns = `
+		SELECT 
+			c.id, c.title, COALESCE(c.description, ''), c.starts_at, c.ends_at, c.rule, c.required_count, c.created_at,
+			(SELECT COUNT(*) FROM challenge_participants p WHERE p.challenge_id = c.id) as participant_count
+		FROM challenges c`
+
+// scanChallenge クエリ結果をチャレンジに変換
+func scanChallenge(scanner interface{ Scan(...interface{}) error }) (*models.Challenge, error) {
+	var challenge models.Challenge
+	var requiredCount sql.NullInt64
+	err := scanner.Scan(
+		&challenge.ID,
+		&challenge.Title,
+		&challenge.Description,
+		&challenge.StartsAt,
+		&challenge.EndsAt,
+		&challenge.Rule,
+		&requiredCount,
+		&challenge.CreatedAt,
+		&challenge.ParticipantCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if requiredCount.Valid {
+		count := int(requiredCount.Int64)
+		challenge.RequiredCount = &count
+	}
+	challenge.TargetMenuIDs = []int{}
+	challenge.TargetCategories = []string{}
+	return &challenge, nil
+}
+
+// loadChallengeTargets チャレンジの対象メニューとカテゴリを設定
+func loadChallengeTargets(challenges []*models.Challenge) error {
+	for _, challenge := range challenges {
+		rows, err := DB.Query("SELECT menu_id, category FROM challenge_targets WHERE challenge_id = ? ORDER BY id", challenge.ID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var menuID sql.NullInt64
+			var category sql.NullString
+			if err := rows.Scan(&menuID, &category); err != nil {
+				rows.Close()
+				return err
+			}
+			if menuID.Valid {
+				challenge.TargetMenuIDs = append(challenge.TargetMenuIDs, int(menuID.Int64))
+			}
+			if category.Valid {
+				challenge.TargetCategories = append(challenge.TargetCategories, category.String)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetChallengeByID 特定のチャレンジを取得
+func GetChallengeByID(id int) (*models.Challenge, error) {
+	challenge, err := scanChallenge(DB.QueryRow(challengeColumns+" WHERE c.id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	if err := loadChallengeTargets([]*models.Challenge{challenge}); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// GetChallenges チャレンジの一覧を取得
+// statusにactive（開催中）/upcoming（開催前）/ended（終了）を指定すると絞り込む
+func GetChallenges(status string) ([]models.Challenge, error) {
+	query := challengeColumns
+	var args []interface{}
+	now := time.Now()
+	switch status {
+	case "active":
+		query += " WHERE c.starts_at <= ? AND c.ends_at > ?"
+		args = append(args, now, now)
+	case "upcoming":
+		query += " WHERE c.starts_at > ?"
+		args = append(args, now)
+	case "ended":
+		query += " WHERE c.ends_at <= ?"
+		args = append(args, now)
+	}
+	query += " ORDER BY c.starts_at DESC, c.id DESC"
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var challengePointers []*models.Challenge
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		challengePointers = append(challengePointers, challenge)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := loadChallengeTargets(challengePointers); err != nil {
+		return nil, err
+	}
+
+	challenges := make([]models.Challenge, 0, len(challengePointers))
+	for _, challenge := range challengePointers {
+		challenges = append(challenges, *challenge)
+	}
+	return challenges, nil
+}
+
+// JoinChallenge ユーザーをチャレンジに参加させる
+// チャレンジが存在しない場合はsql.ErrNoRowsを返す
+func JoinChallenge(challengeID, userID int) error {
+	var endsAt time.Time
+	if err := DB.QueryRow("SELECT ends_at FROM challenges WHERE id = ?", challengeID).Scan(&endsAt); err != nil {
+		return err
+	}
+	if !time.Now().Before(endsAt) {
+		return ErrChallengeEnded
+	}
+
+	result, err := DB.Exec(
+		"INSERT IGNORE INTO challenge_participants (challenge_id, user_id, joined_at) VALUES (?, ?, NOW())",
+		challengeID, userID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrAlreadyJoined
+	}
+	return nil
+}
+
+// challengeTargetMenus チャレンジの対象メニューIDを取得（カテゴリ指定はそのカテゴリの全メニュー）
+func challengeTargetMenus(challenge *models.Challenge) (map[int]bool, error) {
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]bool)
+	for _, category := range challenge.TargetCategories {
+		categories[category] = true
+	}
+
+	targets := make(map[int]bool)
+	for _, menuID := range challenge.TargetMenuIDs {
+		if _, ok := catalog[menuID]; ok {
+			targets[menuID] = true
+		}
+	}
+	for _, menu := range catalog {
+		if categories[menu.Category] {
+			targets[menu.ID] = true
+		}
+	}
+	return targets, nil
+}
+
+// requiredMenuCount チャレンジの達成に必要な対象メニューの種類数
+func requiredMenuCount(challenge *models.Challenge, targets int) int {
+	if challenge.Rule == models.ChallengeRuleCount && challenge.RequiredCount != nil && *challenge.RequiredCount < targets {
+		return *challenge.RequiredCount
+	}
+	return targets
+}
+
+// getChallengeProgresses チャレンジ参加者全員の進捗を取得
+// userIDが0より大きい場合はそのユーザーだけを対象にし、viewerIDのユーザーから見える注文だけを数える
+func getChallengeProgresses(challenge *models.Challenge, userID, viewerID int) ([]models.ChallengeProgress, error) {
+	targets, err := challengeTargetMenus(challenge)
+	if err != nil {
+		return nil, err
+	}
+	required := requiredMenuCount(challenge, len(targets))
+
+	participantQuery := `
+		SELECT p.user_id, u.name, p.joined_at
+		FROM challenge_participants p
+		JOIN users u ON u.id = p.user_id
+		WHERE p.challenge_id = ?`
+	participantArgs := []interface{}{challenge.ID}
+	if userID > 0 {
+		participantQuery += " AND p.user_id = ?"
+		participantArgs = append(participantArgs, userID)
+	}
+
+	rows, err := DB.Query(participantQuery, participantArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progresses := make(map[int]*models.ChallengeProgress)
+	var order []int
+	for rows.Next() {
+		progress := models.ChallengeProgress{
+			ChallengeID:  challenge.ID,
+			EatenMenuIDs: []int{},
+			Required:     required,
+		}
+		if err := rows.Scan(&progress.UserID, &progress.UserName, &progress.JoinedAt); err != nil {
+			return nil, err
+		}
+		progresses[progress.UserID] = &progress
+		order = append(order, progress.UserID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 期間中の参加者の注文を古い順にたどり、達成した時点を求める
+	visible, visibleArgs := visibilityCondition("o", viewerID)
+	orderQuery := `
+		SELECT o.user_id, o.menu_id, o.order_date
+		FROM orders o
+		JOIN challenge_participants p ON p.user_id = o.user_id AND p.challenge_id = ?
+		WHERE o.order_date >= ? AND o.order_date < ? AND ` + visible + `
+		ORDER BY o.order_date, o.id`
+	orderArgs := append([]interface{}{challenge.ID, challenge.StartsAt, challenge.EndsAt}, visibleArgs...)
+	orderRows, err := DB.Query(orderQuery, orderArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer orderRows.Close()
+
+	eaten := make(map[int]map[int]bool)
+	for orderRows.Next() {
+		var orderUserID, menuID int
+		var orderDate time.Time
+		if err := orderRows.Scan(&orderUserID, &menuID, &orderDate); err != nil {
+			return nil, err
+		}
+		progress, ok := progresses[orderUserID]
+		if !ok || !targets[menuID] {
+			continue
+		}
+		if eaten[orderUserID] == nil {
+			eaten[orderUserID] = make(map[int]bool)
+		}
+		if eaten[orderUserID][menuID] {
+			continue
+		}
+		eaten[orderUserID][menuID] = true
+		progress.EatenMenuIDs = append(progress.EatenMenuIDs, menuID)
+		progress.Progress++
+		if !progress.Completed && required > 0 && progress.Progress >= required {
+			completedAt := orderDate
+			progress.Completed = true
+			progress.CompletedAt = &completedAt
+		}
+	}
+	if err = orderRows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ChallengeProgress, 0, len(order))
+	for _, id := range order {
+		result = append(result, *progresses[id])
+	}
+	return result, nil
+}
+
+// GetChallengeProgress チャレンジ参加者の進捗をviewerIDのユーザーから見える注文で取得
+// チャレンジが存在しないか参加していない場合はsql.ErrNoRowsを返す
+func GetChallengeProgress(challengeID, userID, viewerID int) (*models.ChallengeProgress, error) {
+	challenge, err := GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	progresses, err := getChallengeProgresses(challenge, userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(progresses) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &progresses[0], nil
+}
+
+// GetChallengeLeaderboard チャレンジのリーダーボードを取得
+// 達成者は達成日時の早い順、未達成者は進捗の多い順（同じ場合は参加の早い順）に並べる
+// 進捗はviewerIDのユーザーから見える注文だけで数える
+func GetChallengeLeaderboard(challengeID, limit, viewerID int) ([]models.ChallengeLeaderboardEntry, error) {
+	challenge, err := GetChallengeByID(challengeID)
+	if err != nil {
+		return nil, err
+	}
+	progresses, err := getChallengeProgresses(challenge, 0, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(progresses, func(i, j int) bool {
+		a, b := progresses[i], progresses[j]
+		if a.Completed != b.Completed {
+			return a.Completed
+		}
+		if a.Completed && !a.CompletedAt.Equal(*b.CompletedAt) {
+			return a.CompletedAt.Before(*b.CompletedAt)
+		}
+		if a.Progress != b.Progress {
+			return a.Progress > b.Progress
+		}
+		if !a.JoinedAt.Equal(b.JoinedAt) {
+			return a.JoinedAt.Before(b.JoinedAt)
+		}
+		return a.UserID < b.UserID
+	})
+
+	leaderboard := []models.ChallengeLeaderboardEntry{}
+	for i, progress := range progresses {
+		if i >= limit {
+			break
+		}
+		leaderboard = append(leaderboard, models.ChallengeLeaderboardEntry{
+			Rank:              i + 1,
+			ChallengeProgress: progress,
+		})
+	}
+	return leaderboard, nil
+}
@@ -0,0 +1,197 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// GetVisibleOrderOwner viewerIDのユーザーから見える食事記録の記録者を取得
+// 記録が存在しないか見えない場合はsql.ErrNoRowsを返す
+func GetVisibleOrderOwner(orderID, viewerID int) (int, error) {
+	visible, visibleArgs := visibilityCondition("o", viewerID)
+	var ownerID int
+	err := DB.QueryRow(
+		"SELECT o.user_id FROM orders o WHERE o.id = ? AND "+visible,
+		append([]interface{}{orderID}, visibleArgs...)...,
+	).Scan(&ownerID)
+	return ownerID, err
+}
+
+// GetOrderComments 食事記録のコメントを古い順に取得
+func GetOrderComments(orderID int) ([]models.OrderComment, error) {
+	query := `
+		SELECT c.id, c.order_id, c.user_id, u.name, c.body, c.created_at, c.updated_at
+		FROM order_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.order_id = ?
+		ORDER BY c.created_at, c.id
+	`
+
+	rows, err := DB.Query(query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.OrderComment{}
+	for rows.Next() {
+		comment, err := scanOrderComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// scanOrderComment クエリ結果をコメントに変換
+func scanOrderComment(scanner interface{ Scan(...interface{}) error }) (*models.OrderComment, error) {
+	var comment models.OrderComment
+	var updatedAt sql.NullTime
+	err := scanner.Scan(
+		&comment.ID,
+		&comment.OrderID,
+		&comment.UserID,
+		&comment.UserName,
+		&comment.Body,
+		&comment.CreatedAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if updatedAt.Valid {
+		comment.UpdatedAt = &updatedAt.Time
+	}
+	return &comment, nil
+}
+
+// GetOrderComment 特定のコメントを取得
+func GetOrderComment(orderID, commentID int) (*models.OrderComment, error) {
+	query := `
+		SELECT c.id, c.order_id, c.user_id, u.name, c.body, c.created_at, c.updated_at
+		FROM order_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.order_id = ? AND c.id = ?
+	`
+	return scanOrderComment(DB.QueryRow(query, orderID, commentID))
+}
+
+// CreateOrderComment 食事記録にコメントを投稿
+func CreateOrderComment(orderID, userID int, body string) (*models.OrderComment, error) {
+	result, err := DB.Exec(
+		"INSERT INTO order_comments (order_id, user_id, body, created_at) VALUES (?, ?, ?, NOW())",
+		orderID, userID, body,
+	)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return GetOrderComment(orderID, int(id))
+}
+
+// UpdateOrderComment コメントの本文を編集
+func UpdateOrderComment(orderID, commentID int, body string) (*models.OrderComment, error) {
+	_, err := DB.Exec(
+		"UPDATE order_comments SET body = ?, updated_at = NOW() WHERE order_id = ? AND id = ?",
+		body, orderID, commentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return GetOrderComment(orderID, commentID)
+}
+
+// DeleteOrderComment コメントを削除
+func DeleteOrderComment(orderID, commentID int) error {
+	_, err := DB.Exec("DELETE FROM order_comments WHERE order_id = ? AND id = ?", orderID, commentID)
+	return err
+}
+
+// AddOrderReaction 食事記録に絵文字でリアクション
+// 同じ絵文字でリアクション済みの場合はfalseを返す
+func AddOrderReaction(orderID, userID int, emoji string) (bool, error) {
+	result, err := DB.Exec(
+		"INSERT IGNORE INTO order_reactions (order_id, user_id, emoji, created_at) VALUES (?, ?, ?, NOW())",
+		orderID, userID, emoji,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// RemoveOrderReaction 食事記録へのリアクションを取り消す
+func RemoveOrderReaction(orderID, userID int, emoji string) error {
+	_, err := DB.Exec(
+		"DELETE FROM order_reactions WHERE order_id = ? AND user_id = ? AND emoji = ?",
+		orderID, userID, emoji,
+	)
+	return err
+}
+
+// GetOrderReactions 食事記録の絵文字ごとのリアクション数を取得
+func GetOrderReactions(orderID, viewerID int) ([]models.ReactionCount, error) {
+	orders := []models.Order{{ID: orderID}}
+	if err := loadOrderReactions(orders, viewerID); err != nil {
+		return nil, err
+	}
+	return orders[0].Reactions, nil
+}
+
+// loadOrderReactions 食事記録の絵文字ごとのリアクション数を設定
+// 絵文字は最初にリアクションされた順に並べる
+func loadOrderReactions(orders []models.Order, viewerID int) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(orders))
+	placeholders := make([]string, 0, len(orders))
+	args := []interface{}{viewerID}
+	for i := range orders {
+		orders[i].Reactions = []models.ReactionCount{}
+		index[orders[i].ID] = i
+		placeholders = append(placeholders, "?")
+		args = append(args, orders[i].ID)
+	}
+
+	query := `
+		SELECT order_id, emoji, COUNT(*) as reaction_count, MAX(user_id = ?) as reacted_by_me
+		FROM order_reactions
+		WHERE order_id IN (` + strings.Join(placeholders, ", ") + `)
+		GROUP BY order_id, emoji
+		ORDER BY order_id, MIN(created_at), emoji
+	`
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID int
+		var reaction models.ReactionCount
+		if err := rows.Scan(&orderID, &reaction.Emoji, &reaction.Count, &reaction.ReactedByMe); err != nil {
+			return err
+		}
+		i := index[orderID]
+		orders[i].Reactions = append(orders[i].Reactions, reaction)
+	}
+
+	return rows.Err()
+}
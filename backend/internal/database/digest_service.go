@@ -0,0 +1,254 @@
+package database
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// digestFavoriteCount 週間サマリーで順位の変化を載せるよく食べるメニューの数
+const digestFavoriteCount = 3
+
+// PreviousWeek nowの前の週（月曜日〜日曜日）の期間を取得
+func PreviousWeek(now time.Time, loc *time.Location) period.Range {
+	return period.Week(now.In(loc).AddDate(0, 0, -7), loc)
+}
+
+// GetWeeklyDigest ユーザーの指定した週の活動のまとめを作成
+// ユーザーが存在しない場合はsql.ErrNoRowsを返す
+func GetWeeklyDigest(userID int, week period.Range) (*models.WeeklyDigest, error) {
+	user, err := GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := getUserOrders(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &models.WeeklyDigest{
+		UserID:              user.ID,
+		UserName:            user.Name,
+		WeekStart:           week.From.Format(period.DateLayout),
+		WeekEnd:             week.To.AddDate(0, 0, -1).Format(period.DateLayout),
+		NewMenus:            []models.DigestMenu{},
+		FavoriteRankChanges: []models.FavoriteRankChange{},
+	}
+
+	previousWeekStart := week.From.AddDate(0, 0, -7)
+	seen := make(map[int]bool)
+	var weekOrders []userOrder
+	menuCounts := make(map[int]int)
+	menuNames := make(map[int]string)
+	for _, order := range orders {
+		inWeek := !order.OrderDate.Before(week.From) && order.OrderDate.Before(week.To)
+		switch {
+		case inWeek:
+			weekOrders = append(weekOrders, order)
+			digest.Spend += order.amount()
+			// 注文は古い順なので、週より前に食べていないメニューは初めて食べたメニュー
+			if !seen[order.MenuID] {
+				digest.NewMenus = append(digest.NewMenus, models.DigestMenu{
+					MenuID:   order.MenuID,
+					Name:     order.MenuName,
+					Category: order.Category,
+				})
+			}
+		case !order.OrderDate.Before(previousWeekStart) && order.OrderDate.Before(week.From):
+			digest.PreviousWeekSpend += order.amount()
+		}
+		seen[order.MenuID] = true
+
+		if order.OrderDate.Before(week.To) {
+			menuCounts[order.MenuID] += order.Quantity
+			menuNames[order.MenuID] = order.MenuName
+		}
+	}
+	digest.Visits = len(groupVisits(weekOrders))
+
+	err = DB.QueryRow(
+		"SELECT COUNT(DISTINCT menu_id) FROM menu_ratings WHERE user_id = ? AND created_at >= ? AND created_at < ?",
+		userID, week.From, week.To,
+	).Scan(&digest.RatingCount)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites := topMenus(menuCounts, digestFavoriteCount)
+	if len(favorites) == 0 {
+		return digest, nil
+	}
+	ranksAtEnd, err := getSnapshotRanksBefore(favorites, week.To)
+	if err != nil {
+		return nil, err
+	}
+	ranksAtStart, err := getSnapshotRanksBefore(favorites, week.From)
+	if err != nil {
+		return nil, err
+	}
+	for _, menuID := range favorites {
+		change := models.FavoriteRankChange{MenuID: menuID, Name: menuNames[menuID]}
+		if rank, ok := ranksAtEnd[menuID]; ok {
+			change.Rank = &rank
+		}
+		if rank, ok := ranksAtStart[menuID]; ok {
+			change.PreviousRank = &rank
+		}
+		switch {
+		case change.Rank == nil:
+		case change.PreviousRank == nil:
+			change.RankChange = models.RankChangeNew
+		case *change.Rank < *change.PreviousRank:
+			change.RankChange = models.RankChangeUp
+		case *change.Rank > *change.PreviousRank:
+			change.RankChange = models.RankChangeDown
+		default:
+			change.RankChange = models.RankChangeSame
+		}
+		digest.FavoriteRankChanges = append(digest.FavoriteRankChanges, change)
+	}
+
+	return digest, nil
+}
+
+// topMenus 注文数の多い順にメニューIDを最大n件取得（同数の場合はIDの小さい順）
+func topMenus(counts map[int]int, n int) []int {
+	menuIDs := make([]int, 0, len(counts))
+	for menuID := range counts {
+		menuIDs = append(menuIDs, menuID)
+	}
+	sort.Slice(menuIDs, func(i, j int) bool {
+		if counts[menuIDs[i]] != counts[menuIDs[j]] {
+			return counts[menuIDs[i]] > counts[menuIDs[j]]
+		}
+		return menuIDs[i] < menuIDs[j]
+	})
+	if len(menuIDs) > n {
+		menuIDs = menuIDs[:n]
+	}
+	return menuIDs
+}
+
+// getSnapshotRanksBefore before（の日付）より前の最新のスナップショットでの全体ランキングの順位を取得
+func getSnapshotRanksBefore(menuIDs []int, before time.Time) (map[int]int, error) {
+	placeholders := make([]string, 0, len(menuIDs))
+	args := []interface{}{overallRankingScope, overallRankingScope, before.Format(period.DateLayout)}
+	for _, menuID := range menuIDs {
+		placeholders = append(placeholders, "?")
+		args = append(args, menuID)
+	}
+
+	query := `
+		SELECT menu_id, menu_rank
+		FROM ranking_snapshots
+		WHERE scope = ?
+			AND snapshot_date = (
+				SELECT MAX(snapshot_date) FROM ranking_snapshots
+				WHERE scope = ? AND snapshot_date < ?
+			)
+			AND menu_id IN (` + strings.Join(placeholders, ", ") + `)`
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ranks := make(map[int]int)
+	for rows.Next() {
+		var menuID, rank int
+		if err := rows.Scan(&menuID, &rank); err != nil {
+			return nil, err
+		}
+		ranks[menuID] = rank
+	}
+
+	return ranks, rows.Err()
+}
+
+// GetWeeklyDigestRecipients 週間サマリーを受け取る設定にしているユーザーとメールの言語を取得
+func GetWeeklyDigestRecipients() ([]models.WeeklyDigestRecipient, error) {
+	query := `
+		SELECT u.id, u.name, u.email, u.time_zone, u.email_verified_at, u.created_at, u.updated_at, s.mail_language
+		FROM users u
+		JOIN user_settings s ON s.user_id = u.id
+		WHERE s.weekly_digest = TRUE
+		ORDER BY u.id
+	`
+
+	rows, err := DB.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []models.WeeklyDigestRecipient
+	for rows.Next() {
+		var recipient models.WeeklyDigestRecipient
+		var emailVerifiedAt sql.NullTime
+		err := rows.Scan(
+			&recipient.User.ID,
+			&recipient.User.Name,
+			&recipient.User.Email,
+			&recipient.User.TimeZone,
+			&emailVerifiedAt,
+			&recipient.User.CreatedAt,
+			&recipient.User.UpdatedAt,
+			&recipient.MailLanguage,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if emailVerifiedAt.Valid {
+			recipient.User.EmailVerifiedAt = &emailVerifiedAt.Time
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return recipients, nil
+}
+
+// RecordWeeklyDigest 週間サマリーの送信を記録し、通知の作成とメールのキューへの追加を1つのトランザクションで行う
+// emailがnilの場合はメールを送らない
+// その週のサマリーを送信済みの場合は何もせずfalseを返す
+func RecordWeeklyDigest(userID int, weekStart string, notification *models.Notification, email *models.QueuedEmail) (bool, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"INSERT IGNORE INTO weekly_digest_log (user_id, week_start, sent_at) VALUES (?, ?, NOW())",
+		userID, weekStart,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+
+	if _, err := createNotification(tx, notification); err != nil {
+		return false, err
+	}
+	if email != nil {
+		if err := enqueueEmail(tx, email.To, email.Subject, email.Body); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
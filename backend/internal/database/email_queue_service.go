@@ -0,0 +1,80 @@
+package database
+
+import (
+	"time"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// EnqueueEmail メールを送信待ちのキューに追加
+// 実際の送信は定期実行ジョブが行う
+func EnqueueEmail(to, subject, body string) error {
+	return enqueueEmail(DB, to, subject, body)
+}
+
+// enqueueEmail DBまたはトランザクションでメールをキューに追加
+func enqueueEmail(db execer, to, subject, body string) error {
+	_, err := db.Exec(
+		"INSERT INTO email_queue (to_address, subject, body, status, next_attempt_at, created_at) VALUES (?, ?, ?, ?, NOW(), NOW())",
+		to, subject, body, models.EmailStatusPending,
+	)
+	return err
+}
+
+// GetDueEmails 送信時刻になった送信待ちのメールを古い順に取得
+func GetDueEmails(limit int) ([]models.QueuedEmail, error) {
+	query := `
+		SELECT id, to_address, subject, body, attempts
+		FROM email_queue
+		WHERE status = ? AND next_attempt_at <= NOW()
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`
+
+	rows, err := DB.Query(query, models.EmailStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []models.QueuedEmail
+	for rows.Next() {
+		var email models.QueuedEmail
+		if err := rows.Scan(&email.ID, &email.To, &email.Subject, &email.Body, &email.Attempts); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+// MarkEmailSent メールを送信済みにする
+func MarkEmailSent(id int) error {
+	_, err := DB.Exec(
+		"UPDATE email_queue SET status = ?, attempts = attempts + 1, last_error = NULL, sent_at = NOW() WHERE id = ?",
+		models.EmailStatusSent, id,
+	)
+	return err
+}
+
+// MarkEmailFailed メールの送信失敗を記録
+// nextAttemptAtがnilの場合は再送せず失敗として確定する
+func MarkEmailFailed(id int, sendErr error, nextAttemptAt *time.Time) error {
+	if nextAttemptAt == nil {
+		_, err := DB.Exec(
+			"UPDATE email_queue SET status = ?, attempts = attempts + 1, last_error = ? WHERE id = ?",
+			models.EmailStatusFailed, sendErr.Error(), id,
+		)
+		return err
+	}
+	_, err := DB.Exec(
+		"UPDATE email_queue SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?",
+		sendErr.Error(), *nextAttemptAt, id,
+	)
+	return err
+}
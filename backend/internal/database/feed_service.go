@@ -0,0 +1,164 @@
+package database
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// ErrInvalidFeedCursor フィードのカーソルが不正な場合のエラー
+var ErrInvalidFeedCursor = errors.New("invalid feed cursor")
+
+// feedCursor フィードの並び順（日時、種類、キーの降順）で最後に返した項目の位置
+type feedCursor struct {
+	occurredAt time.Time
+	kind       string
+	key        string
+}
+
+// encode カーソルを文字列に変換
+func (c feedCursor) encode() string {
+	raw := fmt.Sprintf("%d|%s|%s", c.occurredAt.UnixNano(), c.kind, c.key)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// parseFeedCursor 文字列からカーソルを取得
+func parseFeedCursor(value string) (*feedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidFeedCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 3)
+	if len(parts) != 3 {
+		return nil, ErrInvalidFeedCursor
+	}
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidFeedCursor
+	}
+	return &feedCursor{occurredAt: time.Unix(0, nanos), kind: parts[1], key: parts[2]}, nil
+}
+
+// queryFeed userFilterで選んだユーザーの食事記録・評価・実績を新しい順に取得
+// userFilterはユーザーIDを返すサブクエリで、viewerIDのユーザーから見える記録だけを含める
+// cursorが空でない場合はその続きから取得する
+func queryFeed(userFilter string, filterArgs []interface{}, viewerID int, cursor string, limit int) (*models.FeedPage, error) {
+	orderVisible, orderVisibleArgs := visibilityCondition("o", viewerID)
+	ratingVisible, ratingVisibleArgs := visibilityCondition("r", viewerID)
+	achievementVisible, achievementVisibleArgs := visibilityCondition("a", viewerID)
+
+	var args []interface{}
+	args = append(args, filterArgs...)
+	args = append(args, orderVisibleArgs...)
+	args = append(args, filterArgs...)
+	args = append(args, ratingVisibleArgs...)
+	args = append(args, filterArgs...)
+	args = append(args, achievementVisibleArgs...)
+
+	query := fmt.Sprintf(`
+		SELECT 
+			feed.kind, feed.item_key, feed.user_id, u.name, feed.occurred_at,
+			feed.menu_id, feed.menu_name, feed.menu_category, feed.quantity, feed.rating, feed.achievement_code
+		FROM (
+			SELECT 
+				'order' as kind, LPAD(o.id, 12, '0') as item_key, o.user_id, o.order_date as occurred_at,
+				COALESCE(o.menu_id, 0) as menu_id,
+				COALESCE(o.menu_name, m.name, '') as menu_name,
+				COALESCE(o.menu_category, m.category, '') as menu_category,
+				COALESCE(o.quantity, 1) as quantity, 0 as rating, '' as achievement_code
+			FROM orders o
+			LEFT JOIN menus m ON m.id = o.menu_id
+			WHERE o.user_id IN (%[1]s) AND %[2]s
+			UNION ALL
+			SELECT 
+				'rating', LPAD(r.id, 12, '0'), r.user_id, r.created_at,
+				COALESCE(r.menu_id, 0), COALESCE(m.name, ''), COALESCE(m.category, ''),
+				0, r.rating, ''
+			FROM menu_ratings r
+			LEFT JOIN menus m ON m.id = r.menu_id
+			WHERE r.user_id IN (%[1]s) AND %[3]s
+			UNION ALL
+			SELECT 
+				'achievement', CONCAT(LPAD(a.user_id, 12, '0'), ':', a.achievement_code), a.user_id, a.unlocked_at,
+				0, '', '', 0, 0, a.achievement_code
+			FROM user_achievements a
+			WHERE a.user_id IN (%[1]s) AND %[4]s
+		) feed
+		JOIN users u ON u.id = feed.user_id`, userFilter, orderVisible, ratingVisible, achievementVisible)
+
+	if cursor != "" {
+		c, err := parseFeedCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += `
+		WHERE feed.occurred_at < ?
+			OR (feed.occurred_at = ? AND (feed.kind < ? OR (feed.kind = ? AND feed.item_key < ?)))`
+		args = append(args, c.occurredAt, c.occurredAt, c.kind, c.kind, c.key)
+	}
+
+	// 次のページがあるか判定するため1件多く取得する
+	query += `
+		ORDER BY feed.occurred_at DESC, feed.kind DESC, feed.item_key DESC
+		LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &models.FeedPage{Items: []models.FeedItem{}}
+	var last feedCursor
+	for rows.Next() {
+		var item models.FeedItem
+		var key string
+		err := rows.Scan(
+			&item.Kind,
+			&key,
+			&item.UserID,
+			&item.UserName,
+			&item.OccurredAt,
+			&item.MenuID,
+			&item.MenuName,
+			&item.MenuCategory,
+			&item.Quantity,
+			&item.Rating,
+			&item.AchievementCode,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page.Items) == limit {
+			page.NextCursor = last.encode()
+			break
+		}
+
+		switch item.Kind {
+		case models.FeedKindOrder, models.FeedKindRating:
+			item.ID, _ = strconv.Atoi(key)
+		case models.FeedKindAchievement:
+			item.AchievementName = achievementName(item.AchievementCode)
+		}
+		page.Items = append(page.Items, item)
+		last = feedCursor{occurredAt: item.OccurredAt, kind: item.Kind, key: key}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetFollowingFeed フォローしているユーザーのアクティビティフィードを取得
+func GetFollowingFeed(userID int, cursor string, limit int) (*models.FeedPage, error) {
+	return queryFeed("SELECT followee_id FROM follows WHERE follower_id = ?", []interface{}{userID}, userID, cursor, limit)
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"gachimatsu-backend/internal/models"
+)
+
+// visibilityCondition viewerIDのユーザーから見える記録に絞り込むSQL条件
+// 本人の記録、公開された記録、フォローしているユーザーのフォロワー限定の記録が見える
+func visibilityCondition(alias string, viewerID int) (string, []interface{}) {
+	condition := `(` + alias + `.user_id = ? OR ` + alias + `.visibility = 'public' OR (` +
+		alias + `.visibility = 'followers' AND EXISTS (
+			SELECT 1 FROM follows vf WHERE vf.follower_id = ? AND vf.followee_id = ` + alias + `.user_id
+		)))`
+	return condition, []interface{}{viewerID, viewerID}
+}
+
+// FollowUser ユーザーをフォロー
+// すでにフォローしている場合は何もせずfalseを返す
+func FollowUser(followerID, followeeID int) (bool, error) {
+	result, err := DB.Exec(
+		"INSERT IGNORE INTO follows (follower_id, followee_id, created_at) VALUES (?, ?, NOW())",
+		followerID, followeeID,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// UnfollowUser ユーザーのフォローを解除
+func UnfollowUser(followerID, followeeID int) error {
+	_, err := DB.Exec("DELETE FROM follows WHERE follower_id = ? AND followee_id = ?", followerID, followeeID)
+	return err
+}
+
+// GetFollowers ユーザーのフォロワーをフォローされた新しい順に取得
+func GetFollowers(userID int) ([]models.FollowUser, error) {
+	query := `
+		SELECT u.id, u.name, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		WHERE f.followee_id = ?
+		ORDER BY f.created_at DESC, u.id
+	`
+	return queryFollowUsers(query, userID)
+}
+
+// GetFollowing ユーザーがフォローしているユーザーをフォローした新しい順に取得
+func GetFollowing(userID int) ([]models.FollowUser, error) {
+	query := `
+		SELECT u.id, u.name, f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = ?
+		ORDER BY f.created_at DESC, u.id
+	`
+	return queryFollowUsers(query, userID)
+}
+
+// queryFollowUsers クエリ結果をフォロー一覧のユーザーに変換
+func queryFollowUsers(query string, args ...interface{}) ([]models.FollowUser, error) {
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.FollowUser{}
+	for rows.Next() {
+		var user models.FollowUser
+		if err := rows.Scan(&user.UserID, &user.Name, &user.FollowedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
@@ -0,0 +1,316 @@
+package database
+
+import (
+	"crypto/rand"
+	"errors"
+	"sort"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// ErrAlreadyGroupMember すでにグループのメンバーである場合のエラー
+var ErrAlreadyGroupMember = errors.New("already a member of the group")
+
+// inviteCodeAlphabet 招待コードに使う文字（読み間違えやすい0/O、1/Iを除く）
+const inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// inviteCodeLength 招待コードの長さ
+const inviteCodeLength = 8
+
+// newInviteCode ランダムな招待コードを生成
+func newInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = inviteCodeAlphabet[int(b)%len(inviteCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+// withGroupCondition グループのメンバーの記録のうちviewerIDのユーザーから見えるものに絞り込む条件をWHERE句に追加
+// tableは絞り込む記録のテーブル名で、groupIDが0の場合は何もしない
+func withGroupCondition(where string, args []interface{}, table string, groupID, viewerID int) (string, []interface{}) {
+	if groupID == 0 {
+		return where, args
+	}
+	where, args = appendCondition(where, args, table+".user_id IN (SELECT user_id FROM group_members WHERE group_id = ?)", groupID)
+	return withVisibilityCondition(where, args, table, viewerID)
+}
+
+// withVisibilityCondition viewerIDのユーザーから見える記録に絞り込む条件をWHERE句に追加
+// tableは絞り込む記録のテーブル名
+func withVisibilityCondition(where string, args []interface{}, table string, viewerID int) (string, []interface{}) {
+	visible, visibleArgs := visibilityCondition(table, viewerID)
+	return appendCondition(where, args, visible, visibleArgs...)
+}
+
+// appendCondition WHERE句に条件を追加（WHERE句が空の場合は作成する）
+func appendCondition(where string, args []interface{}, condition string, conditionArgs ...interface{}) (string, []interface{}) {
+	if where == "" {
+		where = "\n\t\t\tWHERE " + condition
+	} else {
+		where += " AND " + condition
+	}
+	return where, append(args, conditionArgs...)
+}
+
+// CreateGroup グループを作成し、作成したユーザーをオーナーとして追加
+func CreateGroup(name string, ownerID int) (*models.Group, error) {
+	inviteCode, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"INSERT INTO user_groups (name, invite_code, created_at) VALUES (?, ?, NOW())",
+		name, inviteCode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO group_members (group_id, user_id, role, joined_at) VALUES (?, ?, ?, NOW())",
+		id, ownerID, models.GroupRoleOwner,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return GetGroupByID(int(id))
+}
+
+// groupColumns グループ取得時の共通のSELECT句
+const groupColumns = `
+		SELECT 
+			g.id, g.name, g.invite_code, g.created_at,
+			(SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id) as member_count
+		FROM user_groups g`
+
+// GetGroupByID 特定のグループを取得
+func GetGroupByID(id int) (*models.Group, error) {
+	var group models.Group
+	err := DB.QueryRow(groupColumns+" WHERE g.id = ?", id).Scan(
+		&group.ID,
+		&group.Name,
+		&group.InviteCode,
+		&group.CreatedAt,
+		&group.MemberCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetUserGroups ユーザーが参加しているグループを取得
+func GetUserGroups(userID int) ([]models.Group, error) {
+	query := groupColumns + `
+		JOIN group_members me ON me.group_id = g.id AND me.user_id = ?
+		ORDER BY me.joined_at, g.id`
+
+	rows, err := DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.Group{}
+	for rows.Next() {
+		var group models.Group
+		if err := rows.Scan(&group.ID, &group.Name, &group.InviteCode, &group.CreatedAt, &group.MemberCount); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// RegenerateInviteCode グループの招待コードを作り直す（以前のコードは使えなくなる）
+func RegenerateInviteCode(groupID int) (*models.Group, error) {
+	inviteCode, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := DB.Exec("UPDATE user_groups SET invite_code = ? WHERE id = ?", inviteCode, groupID); err != nil {
+		return nil, err
+	}
+	return GetGroupByID(groupID)
+}
+
+// JoinGroupByInviteCode 招待コードのグループにメンバーとして参加
+// 招待コードに一致するグループがない場合はsql.ErrNoRowsを返す
+func JoinGroupByInviteCode(inviteCode string, userID int) (*models.Group, error) {
+	var groupID int
+	if err := DB.QueryRow("SELECT id FROM user_groups WHERE invite_code = ?", inviteCode).Scan(&groupID); err != nil {
+		return nil, err
+	}
+
+	result, err := DB.Exec(
+		"INSERT IGNORE INTO group_members (group_id, user_id, role, joined_at) VALUES (?, ?, ?, NOW())",
+		groupID, userID, models.GroupRoleMember,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, ErrAlreadyGroupMember
+	}
+
+	return GetGroupByID(groupID)
+}
+
+// GetGroupRole ユーザーのグループ内の役割を取得
+// メンバーでない場合はsql.ErrNoRowsを返す
+func GetGroupRole(groupID, userID int) (string, error) {
+	var role string
+	err := DB.QueryRow(
+		"SELECT role FROM group_members WHERE group_id = ? AND user_id = ?",
+		groupID, userID,
+	).Scan(&role)
+	return role, err
+}
+
+// GetGroupMembers グループのメンバーを参加順に取得
+func GetGroupMembers(groupID int) ([]models.GroupMember, error) {
+	query := `
+		SELECT u.id, u.name, gm.role, gm.joined_at
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = ?
+		ORDER BY gm.joined_at, u.id
+	`
+
+	rows, err := DB.Query(query, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.GroupMember{}
+	for rows.Next() {
+		var member models.GroupMember
+		if err := rows.Scan(&member.UserID, &member.Name, &member.Role, &member.JoinedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// RemoveGroupMember グループからメンバーを外す
+func RemoveGroupMember(groupID, userID int) error {
+	_, err := DB.Exec("DELETE FROM group_members WHERE group_id = ? AND user_id = ?", groupID, userID)
+	return err
+}
+
+// GetGroupProgress グループ全体のメニュー制覇状況を取得
+// メンバーが食べたメニューの和集合で集計し、viewerIDのユーザーから見えない食事記録は含めない
+func GetGroupProgress(groupID, viewerID int) (*models.GroupProgress, error) {
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	visible, visibleArgs := visibilityCondition("o", viewerID)
+	query := `
+		SELECT DISTINCT o.menu_id, o.user_id
+		FROM orders o
+		JOIN group_members gm ON gm.user_id = o.user_id AND gm.group_id = ?
+		WHERE ` + visible + `
+		ORDER BY o.menu_id, o.user_id
+	`
+	rows, err := DB.Query(query, append([]interface{}{groupID}, visibleArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	eatenBy := make(map[int][]int)
+	for rows.Next() {
+		var menuID, userID int
+		if err := rows.Scan(&menuID, &userID); err != nil {
+			return nil, err
+		}
+		eatenBy[menuID] = append(eatenBy[menuID], userID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	progress := &models.GroupProgress{
+		GroupID:    groupID,
+		Categories: []models.GroupCategoryProgress{},
+		Menus:      []models.GroupMenuProgress{},
+	}
+	categories := make(map[string]*models.GroupCategoryProgress)
+	for _, menu := range catalog {
+		category, ok := categories[menu.Category]
+		if !ok {
+			category = &models.GroupCategoryProgress{Category: menu.Category}
+			categories[menu.Category] = category
+		}
+		category.Total++
+		progress.Total++
+
+		menuProgress := models.GroupMenuProgress{
+			MenuID:   menu.ID,
+			Name:     menu.Name,
+			Category: menu.Category,
+			EatenBy:  []int{},
+		}
+		if users, ok := eatenBy[menu.ID]; ok {
+			menuProgress.EatenBy = users
+			category.Conquered++
+			progress.Conquered++
+		}
+		progress.Menus = append(progress.Menus, menuProgress)
+	}
+
+	for _, category := range categories {
+		progress.Categories = append(progress.Categories, *category)
+	}
+	sort.Slice(progress.Categories, func(i, j int) bool {
+		return progress.Categories[i].Category < progress.Categories[j].Category
+	})
+	sort.Slice(progress.Menus, func(i, j int) bool {
+		return progress.Menus[i].MenuID < progress.Menus[j].MenuID
+	})
+
+	return progress, nil
+}
+
+// GetGroupFeed グループのメンバーのアクティビティフィードを取得
+func GetGroupFeed(groupID, viewerID int, cursor string, limit int) (*models.FeedPage, error) {
+	return queryFeed("SELECT user_id FROM group_members WHERE group_id = ?", []interface{}{groupID}, viewerID, cursor, limit)
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// LeaderboardQuery ユーザーリーダーボードの取得条件
+type LeaderboardQuery struct {
+	Type   string
+	Period period.Range
+	Limit  int
+	// GroupID 指定した場合はグループのメンバーだけで順位を付ける
+	GroupID int
+	// CallerID 呼び出し元のユーザーID（0の場合は順位を返さない）
+	// 順位は呼び出し元から見える記録だけで付ける（0の場合は公開の記録だけ）
+	CallerID int
+}
+
+// IsValidLeaderboardType リーダーボードの種類が有効か判定
+func IsValidLeaderboardType(kind string) bool {
+	switch kind {
+	case models.LeaderboardConquests, models.LeaderboardVisits, models.LeaderboardRatings:
+		return true
+	}
+	return false
+}
+
+// leaderboardCondition 呼び出し元から見える記録に絞り込む条件をWHERE句に追加
+// グループを指定した場合はさらにグループのメンバーの記録に絞り込む
+func leaderboardCondition(where string, args []interface{}, table string, q LeaderboardQuery) (string, []interface{}) {
+	if q.GroupID != 0 {
+		return withGroupCondition(where, args, table, q.GroupID, q.CallerID)
+	}
+	return withVisibilityCondition(where, args, table, q.CallerID)
+}
+
+// leaderboardSourceSQL ユーザーごとの値と、その値に到達した日時を集計するSQL
+func leaderboardSourceSQL(q LeaderboardQuery) (string, []interface{}, error) {
+	switch q.Type {
+	case models.LeaderboardConquests:
+		// メニューごとの初回注文日時のうち最も遅いものが、現在の種類数に到達した日時
+		where, args := periodCondition("order_date", q.Period)
+		where, args = leaderboardCondition(where, args, "orders", q)
+		return fmt.Sprintf(`
+			SELECT user_id, COUNT(*) as value, MAX(first_ordered_at) as reached_at
+			FROM (
+				SELECT user_id, menu_id, MIN(order_date) as first_ordered_at
+				FROM orders%s
+				GROUP BY user_id, menu_id
+			) first_orders
+			GROUP BY user_id`, where), args, nil
+	case models.LeaderboardVisits:
+		// 同じ日時の注文はまとめて1回の来店として数える
+		where, args := periodCondition("order_date", q.Period)
+		where, args = leaderboardCondition(where, args, "orders", q)
+		return fmt.Sprintf(`
+			SELECT user_id, COUNT(DISTINCT order_date) as value, MAX(order_date) as reached_at
+			FROM orders%s
+			GROUP BY user_id`, where), args, nil
+	case models.LeaderboardRatings:
+		where, args := periodCondition("created_at", q.Period)
+		where, args = leaderboardCondition(where, args, "menu_ratings", q)
+		return fmt.Sprintf(`
+			SELECT user_id, COUNT(DISTINCT menu_id) as value, MAX(created_at) as reached_at
+			FROM menu_ratings%s
+			GROUP BY user_id`, where), args, nil
+	}
+	return "", nil, fmt.Errorf("unknown leaderboard type: %s", q.Type)
+}
+
+// GetLeaderboard ユーザーリーダーボードを取得
+// リーダーボードを非公開にしているユーザーは除外し、同じ値の場合は先に到達したユーザーを上位にする
+func GetLeaderboard(q LeaderboardQuery) (*models.Leaderboard, error) {
+	source, args, err := leaderboardSourceSQL(q)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT user_id, name, value, reached_at, leaderboard_rank
+		FROM (
+			SELECT 
+				s.user_id, u.name, s.value, s.reached_at,
+				ROW_NUMBER() OVER (ORDER BY s.value DESC, s.reached_at ASC, s.user_id) as leaderboard_rank
+			FROM (%s
+			) s
+			JOIN users u ON u.id = s.user_id
+			LEFT JOIN user_settings us ON us.user_id = s.user_id
+			WHERE COALESCE(us.leaderboard_opt_out, FALSE) = FALSE
+		) ranked
+		WHERE leaderboard_rank <= ? OR user_id = ?
+		ORDER BY leaderboard_rank
+	`, source)
+	args = append(args, q.Limit, q.CallerID)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leaderboard := &models.Leaderboard{
+		Type:    q.Type,
+		Entries: []models.LeaderboardEntry{},
+	}
+	if !q.Period.From.IsZero() {
+		from := q.Period.From
+		leaderboard.From = &from
+	}
+	if !q.Period.To.IsZero() {
+		to := q.Period.To
+		leaderboard.To = &to
+	}
+
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		if err := rows.Scan(&entry.UserID, &entry.UserName, &entry.Value, &entry.ReachedAt, &entry.Rank); err != nil {
+			return nil, err
+		}
+		if entry.Rank <= q.Limit {
+			leaderboard.Entries = append(leaderboard.Entries, entry)
+		}
+		if q.CallerID > 0 && entry.UserID == q.CallerID {
+			me := entry
+			leaderboard.Me = &me
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return leaderboard, nil
+}
@@ -0,0 +1,122 @@
+package database
+
+import (
+	"gachimatsu-backend/internal/models"
+)
+
+// GetMenuByID 特定のメニューを取得
+func GetMenuByID(id int) (*models.Menu, error) {
+	query := `
+		SELECT 
+			id, name, category, price,
+			COALESCE(description, ''), COALESCE(image_url, ''), COALESCE(is_available, TRUE),
+			created_at, updated_at
+		FROM menus
+		WHERE id = ?
+	`
+
+	var menu models.Menu
+	err := DB.QueryRow(query, id).Scan(
+		&menu.ID,
+		&menu.Name,
+		&menu.Category,
+		&menu.Price,
+		&menu.Description,
+		&menu.ImageURL,
+		&menu.IsAvailable,
+		&menu.CreatedAt,
+		&menu.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &menu, nil
+}
+
+// UpdateMenu メニューを更新
+// 価格が変わった場合は価格履歴に記録する
+// メニューが存在しない場合はsql.ErrNoRowsを返す
+func UpdateMenu(id int, req models.UpdateMenuRequest) (*models.Menu, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var currentPrice int
+	err = tx.QueryRow("SELECT price FROM menus WHERE id = ? FOR UPDATE", id).Scan(&currentPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE menus SET
+			name = COALESCE(?, name),
+			category = COALESCE(?, category),
+			price = COALESCE(?, price),
+			description = COALESCE(?, description),
+			image_url = COALESCE(?, image_url),
+			is_available = COALESCE(?, is_available)
+		WHERE id = ?
+	`
+	_, err = tx.Exec(query, req.Name, req.Category, req.Price, req.Description, req.ImageURL, req.IsAvailable, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Price != nil && *req.Price != currentPrice {
+		_, err = tx.Exec(
+			"INSERT INTO menu_price_history (menu_id, old_price, new_price, changed_at) VALUES (?, ?, ?, NOW())",
+			id, currentPrice, *req.Price,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	InvalidateRankingCache()
+	return GetMenuByID(id)
+}
+
+// GetMenuPriceHistory メニューの価格履歴を古い順に取得
+func GetMenuPriceHistory(menuID int) ([]models.PriceChange, error) {
+	query := `
+		SELECT id, menu_id, old_price, new_price, changed_at
+		FROM menu_price_history
+		WHERE menu_id = ?
+		ORDER BY changed_at, id
+	`
+
+	rows, err := DB.Query(query, menuID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.PriceChange{}
+	for rows.Next() {
+		var change models.PriceChange
+		err := rows.Scan(
+			&change.ID,
+			&change.MenuID,
+			&change.OldPrice,
+			&change.NewPrice,
+			&change.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// challengeNotificationWindow チャレンジの開始・終了を通知する対象期間
+// この期間より前に開始・終了したチャレンジは通知しない
+const challengeNotificationWindow = 24 * time.Hour
+
+// execer DBとトランザクションのどちらでもクエリを実行できるようにするインターフェース
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// CreateNotification ユーザーへの通知を作成
+// ユーザーがその種類の通知を受信しない設定にしている場合や、
+// 同じdedupe_keyの通知が作成済みの場合は作成せずfalseを返す
+func CreateNotification(notification *models.Notification) (bool, error) {
+	return createNotification(DB, notification)
+}
+
+// createNotification DBまたはトランザクションで通知を作成
+func createNotification(db execer, notification *models.Notification) (bool, error) {
+	var dedupeKey sql.NullString
+	if notification.DedupeKey != "" {
+		dedupeKey = sql.NullString{String: notification.DedupeKey, Valid: true}
+	}
+
+	query := `
+		INSERT IGNORE INTO notifications (user_id, type, actor_id, order_id, message, dedupe_key, created_at)
+		SELECT ?, ?, ?, ?, ?, ?, NOW() FROM DUAL
+		WHERE NOT EXISTS (
+			SELECT 1 FROM notification_preferences 
+			WHERE user_id = ? AND type = ? AND enabled = FALSE
+		)
+	`
+	result, err := db.Exec(query,
+		notification.UserID,
+		notification.Type,
+		notification.ActorID,
+		notification.OrderID,
+		notification.Message,
+		dedupeKey,
+		notification.UserID,
+		notification.Type,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return false, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+	notification.ID = int(id)
+	return true, nil
+}
+
+// GetNotifications ユーザーの通知を新しい順に取得
+// unreadOnlyがtrueの場合は未読の通知だけ、beforeIDが0より大きい場合はそれより古い通知だけを返す
+func GetNotifications(userID int, unreadOnly bool, beforeID int, limit int) ([]models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, actor_id, order_id, message, read_at, created_at
+		FROM notifications
+		WHERE user_id = ?`
+	args := []interface{}{userID}
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	if beforeID > 0 {
+		query += " AND id < ?"
+		args = append(args, beforeID)
+	}
+	query += `
+		ORDER BY id DESC
+		LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		var actorID, orderID sql.NullInt64
+		var readAt sql.NullTime
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&actorID,
+			&orderID,
+			&notification.Message,
+			&readAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			notification.ActorID = &id
+		}
+		if orderID.Valid {
+			id := int(orderID.Int64)
+			notification.OrderID = &id
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetUnreadNotificationCount ユーザーの未読の通知数を取得
+func GetUnreadNotificationCount(userID int) (int, error) {
+	var count int
+	err := DB.QueryRow("SELECT COUNT(*) FROM notifications WHERE user_id = ? AND read_at IS NULL", userID).Scan(&count)
+	return count, err
+}
+
+// MarkNotificationRead 通知を既読にする
+// ユーザーの通知が存在しない場合はsql.ErrNoRowsを返す
+func MarkNotificationRead(userID, notificationID int) error {
+	var id int
+	err := DB.QueryRow("SELECT id FROM notifications WHERE id = ? AND user_id = ?", notificationID, userID).Scan(&id)
+	if err != nil {
+		return err
+	}
+	_, err = DB.Exec("UPDATE notifications SET read_at = NOW() WHERE id = ? AND read_at IS NULL", notificationID)
+	return err
+}
+
+// MarkAllNotificationsRead ユーザーの未読の通知をすべて既読にし、既読にした件数を返す
+func MarkAllNotificationsRead(userID int) (int, error) {
+	result, err := DB.Exec("UPDATE notifications SET read_at = NOW() WHERE user_id = ? AND read_at IS NULL", userID)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return int(rowsAffected), err
+}
+
+// GetNotificationPreferences ユーザーの通知の受信設定を全種類取得
+// 設定していない種類は受信する
+func GetNotificationPreferences(userID int) ([]models.NotificationPreference, error) {
+	rows, err := DB.Query("SELECT type, enabled FROM notification_preferences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	saved := make(map[string]bool)
+	for rows.Next() {
+		var kind string
+		var enabled bool
+		if err := rows.Scan(&kind, &enabled); err != nil {
+			return nil, err
+		}
+		saved[kind] = enabled
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	preferences := make([]models.NotificationPreference, 0, len(models.NotificationTypes))
+	for _, kind := range models.NotificationTypes {
+		enabled, ok := saved[kind]
+		preferences = append(preferences, models.NotificationPreference{
+			Type:    kind,
+			Enabled: !ok || enabled,
+		})
+	}
+	return preferences, nil
+}
+
+// UpdateNotificationPreferences ユーザーの通知の受信設定を更新
+func UpdateNotificationPreferences(userID int, preferences []models.NotificationPreference) ([]models.NotificationPreference, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notification_preferences (user_id, type, enabled) VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE enabled = VALUES(enabled)
+	`
+	for _, preference := range preferences {
+		if _, err := tx.Exec(query, userID, preference.Type, preference.Enabled); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return GetNotificationPreferences(userID)
+}
+
+// NotifyChallengeEvents 開始・終了したチャレンジを参加者に通知し、作成した通知の件数を返す
+// 定期実行ジョブから呼び出し、同じチャレンジの開始・終了は1回だけ通知する
+func NotifyChallengeEvents() (int, error) {
+	now := time.Now()
+	since := now.Add(-challengeNotificationWindow)
+
+	events := []struct {
+		kind    string
+		column  string
+		message string
+	}{
+		{models.NotificationChallengeStart, "starts_at", "」が始まりました"},
+		{models.NotificationChallengeEnd, "ends_at", "」が終了しました。結果を確認しましょう"},
+	}
+
+	created := 0
+	for _, event := range events {
+		query := `
+			INSERT IGNORE INTO notifications (user_id, type, message, dedupe_key, created_at)
+			SELECT p.user_id, ?, CONCAT('チャレンジ「', c.title, ?), CONCAT(?, ':', c.id), NOW()
+			FROM challenges c
+			JOIN challenge_participants p ON p.challenge_id = c.id
+			LEFT JOIN notification_preferences np ON np.user_id = p.user_id AND np.type = ?
+			WHERE c.` + event.column + ` <= ? AND c.` + event.column + ` > ?
+				AND COALESCE(np.enabled, TRUE)
+		`
+		result, err := DB.Exec(query, event.kind, event.message, event.kind, event.kind, now, since)
+		if err != nil {
+			return created, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return created, err
+		}
+		created += int(rowsAffected)
+	}
+
+	return created, nil
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"time"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// CreateOrder 食事記録を作成
+// 注文時点のメニューの単価・名前・カテゴリを記録に保存する
+// メニューが存在しない場合はsql.ErrNoRowsを返す
+func CreateOrder(order *models.Order) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow("SELECT name, category, price FROM menus WHERE id = ?", order.MenuID).Scan(
+		&order.MenuName,
+		&order.MenuCategory,
+		&order.UnitPrice,
+	)
+	if err != nil {
+		return err
+	}
+
+	if order.OrderDate.IsZero() {
+		order.OrderDate = time.Now()
+	}
+
+	query := `
+		INSERT INTO orders (user_id, menu_id, quantity, unit_price, menu_name, menu_category, order_date, visibility) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := tx.Exec(query,
+		order.UserID,
+		order.MenuID,
+		order.Quantity,
+		order.UnitPrice,
+		order.MenuName,
+		order.MenuCategory,
+		order.OrderDate,
+		order.Visibility,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	order.ID = int(id)
+	order.Reactions = []models.ReactionCount{}
+	InvalidateRankingCache()
+	return nil
+}
+
+// GetOrdersByUserID ユーザーの食事記録を新しい順に取得
+// viewerIDのユーザーから見える公開範囲の記録だけを返す（0の場合は公開された記録のみ）
+func GetOrdersByUserID(userID, viewerID int, limit int) ([]models.Order, error) {
+	visible, visibleArgs := visibilityCondition("o", viewerID)
+	query := `
+		SELECT 
+			o.id,
+			o.user_id,
+			o.menu_id,
+			COALESCE(o.quantity, 1),
+			COALESCE(o.unit_price, m.price, 0),
+			COALESCE(o.menu_name, m.name, ''),
+			COALESCE(o.menu_category, m.category, ''),
+			o.order_date,
+			o.visibility,
+			(SELECT COUNT(*) FROM order_comments c WHERE c.order_id = o.id) as comment_count
+		FROM orders o
+		LEFT JOIN menus m ON m.id = o.menu_id
+		WHERE o.user_id = ? AND ` + visible + `
+		ORDER BY o.order_date DESC, o.id DESC
+		LIMIT ?
+	`
+
+	args := append([]interface{}{userID}, visibleArgs...)
+	args = append(args, limit)
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []models.Order{}
+	for rows.Next() {
+		var order models.Order
+		err := rows.Scan(
+			&order.ID,
+			&order.UserID,
+			&order.MenuID,
+			&order.Quantity,
+			&order.UnitPrice,
+			&order.MenuName,
+			&order.MenuCategory,
+			&order.OrderDate,
+			&order.Visibility,
+			&order.CommentCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := loadOrderReactions(orders, viewerID); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"math"
+	"sort"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// GetUserProgress ユーザーのメニューの制覇状況を取得
+// viewerIDのユーザーから見える食事記録だけを数える（本人には非公開の記録も含む）
+func GetUserProgress(user *models.User, viewerID int) (*models.UserProgress, error) {
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	visible, visibleArgs := visibilityCondition("o", viewerID)
+	rows, err := DB.Query(
+		"SELECT DISTINCT o.menu_id FROM orders o WHERE o.user_id = ? AND "+visible,
+		append([]interface{}{user.ID}, visibleArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	eaten := make(map[int]bool)
+	for rows.Next() {
+		var menuID int
+		if err := rows.Scan(&menuID); err != nil {
+			return nil, err
+		}
+		eaten[menuID] = true
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	progress := &models.UserProgress{
+		UserID:     user.ID,
+		UserName:   user.Name,
+		Categories: []models.CategoryProgress{},
+	}
+	categories := make(map[string]*models.CategoryProgress)
+	for _, menu := range catalog {
+		category, ok := categories[menu.Category]
+		if !ok {
+			category = &models.CategoryProgress{Category: menu.Category}
+			categories[menu.Category] = category
+		}
+		category.Total++
+		progress.Total++
+		if eaten[menu.ID] {
+			category.Conquered++
+			progress.Conquered++
+		}
+	}
+
+	for _, category := range categories {
+		progress.Categories = append(progress.Categories, *category)
+	}
+	sort.Slice(progress.Categories, func(i, j int) bool {
+		return progress.Categories[i].Category < progress.Categories[j].Category
+	})
+	progress.Percentage = ProgressPercentage(progress.Conquered, progress.Total)
+
+	return progress, nil
+}
+
+// ProgressPercentage 制覇率を小数点以下1桁で計算
+func ProgressPercentage(conquered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(conquered)/float64(total)*1000) / 10
+}
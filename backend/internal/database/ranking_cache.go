@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gachimatsu-backend/internal/cache"
+	"gachimatsu-backend/internal/models"
+)
+
+// defaultRankingCacheTTLSeconds ランキングキャッシュの有効期限（秒）のデフォルト値
+const defaultRankingCacheTTLSeconds = 60
+
+// maxRankingCacheEntries ランキングキャッシュに保持する結果の最大件数
+// キーには呼び出し元が指定する条件が含まれるため、上限を超えた分は古いものから破棄する
+const maxRankingCacheEntries = 1000
+
+// rankingCacheTTL ランキングキャッシュの有効期限（0以下の場合はキャッシュしない）
+var rankingCacheTTL = time.Duration(getEnvFloat("RANKING_CACHE_TTL_SECONDS", defaultRankingCacheTTLSeconds) * float64(time.Second))
+
+// rankingResultCache ランキング結果のキャッシュ
+var rankingResultCache = cache.New(maxRankingCacheEntries)
+
+// InvalidateRankingCache ランキング結果のキャッシュを破棄
+// 注文や評価が追加されたときに呼び出す
+func InvalidateRankingCache() {
+	rankingResultCache.Clear()
+}
+
+// rankingCacheKey 取得条件からキャッシュのキーを作成
+func rankingCacheKey(q models.RankingQuery) string {
+	priorMean := "global"
+	if q.Scoring.PriorMean != nil {
+		priorMean = fmt.Sprint(*q.Scoring.PriorMean)
+	}
+	return fmt.Sprintf("%d|%s|%s|%s|%v|%v|%s|%v|%v|%d|%d",
+		q.Limit,
+		q.Period.From.Format(time.RFC3339Nano),
+		q.Period.To.Format(time.RFC3339Nano),
+		q.Scoring.Method,
+		q.Scoring.OrderWeight,
+		q.Scoring.RatingWeight,
+		priorMean,
+		q.Scoring.PriorWeight,
+		q.Scoring.WilsonZ,
+		q.GroupID,
+		q.ViewerID,
+	)
+}
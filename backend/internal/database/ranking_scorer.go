@@ -0,0 +1,102 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// rankingScorer ランキングスコアの計算方法を表すインターフェース
+// scoreSQLはランキング集計の列（o.order_count, r.avg_rating, r.total_rating,
+// r.rating_sum, r.positive_rating）からスコアを計算するSQL式と引数を返す
+type rankingScorer interface {
+	scoreSQL() (string, []interface{})
+}
+
+// rankingScorers 計算方法の名前とスコア計算の対応表
+var rankingScorers = map[string]func(models.ScoringOptions) rankingScorer{
+	models.ScoringWeighted: func(opts models.ScoringOptions) rankingScorer { return weightedScorer{opts} },
+	models.ScoringBayesian: func(opts models.ScoringOptions) rankingScorer { return bayesianScorer{opts} },
+	models.ScoringWilson:   func(opts models.ScoringOptions) rankingScorer { return wilsonScorer{opts} },
+}
+
+// IsValidScoringMethod スコアの計算方法が存在するかどうかを返す
+func IsValidScoringMethod(method string) bool {
+	_, ok := rankingScorers[method]
+	return ok
+}
+
+// newRankingScorer 取得条件に応じたスコア計算を生成
+func newRankingScorer(opts models.ScoringOptions) (rankingScorer, error) {
+	method := opts.Method
+	if method == "" {
+		method = models.ScoringWeighted
+	}
+	newScorer, ok := rankingScorers[method]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring method %q", method)
+	}
+	return newScorer(opts), nil
+}
+
+// DefaultScoringOptions 環境変数からスコア計算のデフォルト設定を取得
+func DefaultScoringOptions() models.ScoringOptions {
+	return models.ScoringOptions{
+		Method:       getEnv("RANKING_SCORING", models.ScoringWeighted),
+		OrderWeight:  getEnvFloat("RANKING_ORDER_WEIGHT", 0.7),
+		RatingWeight: getEnvFloat("RANKING_RATING_WEIGHT", 0.3),
+		PriorWeight:  getEnvFloat("RANKING_BAYES_PRIOR_WEIGHT", 5),
+		WilsonZ:      getEnvFloat("RANKING_WILSON_Z", 1.96),
+	}
+}
+
+// getEnvFloat 環境変数を数値として取得（デフォルト値付き）
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// weightedScorer 注文数と評価合計の加重和
+type weightedScorer struct {
+	opts models.ScoringOptions
+}
+
+func (s weightedScorer) scoreSQL() (string, []interface{}) {
+	return `(COALESCE(o.order_count, 0) * ? + COALESCE(r.rating_sum, 0) * ?)`,
+		[]interface{}{s.opts.OrderWeight, s.opts.RatingWeight}
+}
+
+// bayesianScorer 事前分布で補正した評価のベイズ平均
+// 評価件数が少ないメニューほど事前平均に近づく
+type bayesianScorer struct {
+	opts models.ScoringOptions
+}
+
+func (s bayesianScorer) scoreSQL() (string, []interface{}) {
+	if s.opts.PriorMean == nil {
+		return `((? * (SELECT COALESCE(AVG(rating), 0) FROM menu_ratings) + COALESCE(r.rating_sum, 0)) / (? + COALESCE(r.total_rating, 0)))`,
+			[]interface{}{s.opts.PriorWeight, s.opts.PriorWeight}
+	}
+	return `((? * ? + COALESCE(r.rating_sum, 0)) / (? + COALESCE(r.total_rating, 0)))`,
+		[]interface{}{s.opts.PriorWeight, *s.opts.PriorMean, s.opts.PriorWeight}
+}
+
+// wilsonScorer 高評価の割合のWilsonスコア区間の下限
+type wilsonScorer struct {
+	opts models.ScoringOptions
+}
+
+func (s wilsonScorer) scoreSQL() (string, []interface{}) {
+	z := s.opts.WilsonZ
+	z2 := z * z
+	return `(CASE WHEN COALESCE(r.total_rating, 0) = 0 THEN 0 ELSE
+			(r.positive_rating / r.total_rating + ? / (2 * r.total_rating)
+				- ? * SQRT((r.positive_rating / r.total_rating) * (1 - r.positive_rating / r.total_rating) / r.total_rating
+					+ ? / (4 * r.total_rating * r.total_rating)))
+			/ (1 + ? / r.total_rating) END)`,
+		[]interface{}{z2, z, z2, z2}
+}
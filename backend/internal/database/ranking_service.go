@@ -1,11 +1,33 @@
 package database
 
 import (
+	"database/sql"
+	"strings"
+	"time"
+
 	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
 )
 
-// GetPopularMenus 人気メニューランキングを取得
-func GetPopularMenus(limit int) ([]models.PopularMenu, error) {
+// defaultTrendingHalfLifeDays トレンドランキングの半減期（日数）のデフォルト値
+const defaultTrendingHalfLifeDays = 7
+
+// rankingOrderSQL ランキングの並び順（同点の場合は注文数、メニューIDの順）
+const rankingOrderSQL = "score DESC, order_count DESC, menu_id"
+
+// rankingSourceSQL ランキング集計に共通するSELECT句とFROM句を組み立てる
+// 注文と評価は集計期間（とグループ）で絞り込み、スコアは指定された計算方法で算出する
+func rankingSourceSQL(q models.RankingQuery) (string, []interface{}, error) {
+	scorer, err := newRankingScorer(q.Scoring)
+	if err != nil {
+		return "", nil, err
+	}
+	scoreExpr, scoreArgs := scorer.scoreSQL()
+	orderWhere, orderArgs := periodCondition("order_date", q.Period)
+	orderWhere, orderArgs = withGroupCondition(orderWhere, orderArgs, "orders", q.GroupID, q.ViewerID)
+	ratingWhere, ratingArgs := periodCondition("created_at", q.Period)
+	ratingWhere, ratingArgs = withGroupCondition(ratingWhere, ratingArgs, "menu_ratings", q.GroupID, q.ViewerID)
+
 	query := `
 		SELECT 
 			m.id as menu_id,
@@ -14,27 +36,49 @@ func GetPopularMenus(limit int) ([]models.PopularMenu, error) {
 			m.price,
 			COALESCE(o.order_count, 0) as order_count,
 			COALESCE(r.avg_rating, 0) as avg_rating,
-			COALESCE(r.total_rating, 0) as total_rating
+			COALESCE(r.total_rating, 0) as total_rating,
+			` + scoreExpr + ` as score
 		FROM menus m
 		LEFT JOIN (
 			SELECT menu_id, COUNT(*) as order_count
-			FROM orders 
+			FROM orders` + orderWhere + `
 			GROUP BY menu_id
 		) o ON m.id = o.menu_id
 		LEFT JOIN (
-			SELECT menu_id, AVG(rating) as avg_rating, COUNT(*) as total_rating
-			FROM menu_ratings 
+			SELECT 
+				menu_id,
+				AVG(rating) as avg_rating,
+				COUNT(*) as total_rating,
+				SUM(rating) as rating_sum,
+				SUM(rating >= 4) as positive_rating
+			FROM menu_ratings` + ratingWhere + `
 			GROUP BY menu_id
-		) r ON m.id = r.menu_id
-		ORDER BY (COALESCE(o.order_count, 0) * 0.7 + COALESCE(r.avg_rating, 0) * COALESCE(r.total_rating, 0) * 0.3) DESC
-		LIMIT ?`
+		) r ON m.id = r.menu_id`
 
-	rows, err := DB.Query(query, limit)
-	if err != nil {
-		return nil, err
+	args := append(scoreArgs, orderArgs...)
+	return query, append(args, ratingArgs...), nil
+}
+
+// periodCondition 集計期間で絞り込むWHERE句と引数を組み立てる
+func periodCondition(column string, rng period.Range) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if !rng.From.IsZero() {
+		conditions = append(conditions, column+" >= ?")
+		args = append(args, rng.From)
 	}
-	defer rows.Close()
+	if !rng.To.IsZero() {
+		conditions = append(conditions, column+" < ?")
+		args = append(args, rng.To)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return "\n\t\t\tWHERE " + strings.Join(conditions, " AND "), args
+}
 
+// scanPopularMenus クエリ結果を人気メニューのスライスに変換
+func scanPopularMenus(rows *sql.Rows) ([]models.PopularMenu, error) {
 	var popularMenus []models.PopularMenu
 	for rows.Next() {
 		var menu models.PopularMenu
@@ -46,6 +90,7 @@ func GetPopularMenus(limit int) ([]models.PopularMenu, error) {
 			&menu.OrderCount,
 			&menu.AvgRating,
 			&menu.TotalRating,
+			&menu.Score,
 		)
 		if err != nil {
 			return nil, err
@@ -53,48 +98,151 @@ func GetPopularMenus(limit int) ([]models.PopularMenu, error) {
 		popularMenus = append(popularMenus, menu)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
 	return popularMenus, nil
 }
 
+// setRanks 並び順から順位を設定し、比較可能な場合は前日からの順位変動を設定
+func setRanks(q models.RankingQuery, scope string, menus []models.PopularMenu) error {
+	for i := range menus {
+		menus[i].Rank = i + 1
+	}
+
+	if !isSnapshotComparable(q) {
+		return nil
+	}
+	previous, err := getPreviousSnapshotRanks()
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		applyRankChanges(previous[scope], menus)
+	}
+	return nil
+}
+
+// GetPopularMenus 人気メニューランキングを取得
+func GetPopularMenus(q models.RankingQuery) ([]models.PopularMenu, error) {
+	key := "popular|" + rankingCacheKey(q)
+	if cached, ok := rankingResultCache.Get(key); ok {
+		return cached.([]models.PopularMenu), nil
+	}
+
+	source, args, err := rankingSourceSQL(q)
+	if err != nil {
+		return nil, err
+	}
+	query := source + `
+		ORDER BY ` + rankingOrderSQL + `
+		LIMIT ?`
+
+	rows, err := DB.Query(query, append(args, q.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	popularMenus, err := scanPopularMenus(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := setRanks(q, overallRankingScope, popularMenus); err != nil {
+		return nil, err
+	}
+
+	rankingResultCache.Set(key, popularMenus, rankingCacheTTL)
+	return popularMenus, nil
+}
+
 // GetPopularMenusByCategory カテゴリ別人気メニューを取得
-func GetPopularMenusByCategory(category string, limit int) ([]models.PopularMenu, error) {
-	query := `
-		SELECT 
-			m.id as menu_id,
-			m.name,
-			m.category,
-			m.price,
-			COALESCE(o.order_count, 0) as order_count,
-			COALESCE(r.avg_rating, 0) as avg_rating,
-			COALESCE(r.total_rating, 0) as total_rating
-		FROM menus m
-		LEFT JOIN (
-			SELECT menu_id, COUNT(*) as order_count
-			FROM orders 
-			GROUP BY menu_id
-		) o ON m.id = o.menu_id
-		LEFT JOIN (
-			SELECT menu_id, AVG(rating) as avg_rating, COUNT(*) as total_rating
-			FROM menu_ratings 
-			GROUP BY menu_id
-		) r ON m.id = r.menu_id
+func GetPopularMenusByCategory(category string, q models.RankingQuery) ([]models.PopularMenu, error) {
+	key := "popular-category|" + category + "|" + rankingCacheKey(q)
+	if cached, ok := rankingResultCache.Get(key); ok {
+		return cached.([]models.PopularMenu), nil
+	}
+
+	source, args, err := rankingSourceSQL(q)
+	if err != nil {
+		return nil, err
+	}
+	query := source + `
 		WHERE m.category = ?
-		ORDER BY (COALESCE(o.order_count, 0) * 0.7 + COALESCE(r.avg_rating, 0) * COALESCE(r.total_rating, 0) * 0.3) DESC
+		ORDER BY ` + rankingOrderSQL + `
 		LIMIT ?`
 
-	rows, err := DB.Query(query, category, limit)
+	rows, err := DB.Query(query, append(args, category, q.Limit)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var popularMenus []models.PopularMenu
+	popularMenus, err := scanPopularMenus(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := setRanks(q, categoryRankingScope(category), popularMenus); err != nil {
+		return nil, err
+	}
+
+	rankingResultCache.Set(key, popularMenus, rankingCacheTTL)
+	return popularMenus, nil
+}
+
+// GetMenuRanking 全体ランキングとカテゴリ別ランキングを取得
+// 結果はキャッシュされ、有効期限内の同じ取得条件では再計算しない
+func GetMenuRanking(q models.RankingQuery) (*models.MenuRanking, error) {
+	key := "menu-ranking|" + rankingCacheKey(q)
+	if cached, ok := rankingResultCache.Get(key); ok {
+		return cached.(*models.MenuRanking), nil
+	}
+
+	ranking, err := queryMenuRanking(q)
+	if err != nil {
+		return nil, err
+	}
+
+	rankingResultCache.Set(key, ranking, rankingCacheTTL)
+	return ranking, nil
+}
+
+// queryMenuRanking 全体順位とカテゴリ内順位をウィンドウ関数で求め、1回のクエリでランキングを取得
+func queryMenuRanking(q models.RankingQuery) (*models.MenuRanking, error) {
+	ranking := &models.MenuRanking{
+		CategoryRankings: make(map[string][]models.PopularMenu),
+	}
+
+	source, args, err := rankingSourceSQL(q)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT 
+			menu_id, name, category, price, order_count, avg_rating, total_rating, score,
+			overall_rank, category_rank
+		FROM (
+			SELECT 
+				ranked.*,
+				ROW_NUMBER() OVER (ORDER BY ` + rankingOrderSQL + `) as overall_rank,
+				ROW_NUMBER() OVER (PARTITION BY category ORDER BY ` + rankingOrderSQL + `) as category_rank
+			FROM (` + source + `
+			) ranked
+		) t
+		WHERE overall_rank <= ? OR category_rank <= ?
+		ORDER BY category, category_rank`
+
+	rows, err := DB.Query(query, append(args, q.Limit, q.Limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overall := make(map[int]models.PopularMenu)
 	for rows.Next() {
 		var menu models.PopularMenu
+		var overallRank, categoryRank int
 		err := rows.Scan(
 			&menu.MenuID,
 			&menu.Name,
@@ -103,63 +251,127 @@ func GetPopularMenusByCategory(category string, limit int) ([]models.PopularMenu
 			&menu.OrderCount,
 			&menu.AvgRating,
 			&menu.TotalRating,
+			&menu.Score,
+			&overallRank,
+			&categoryRank,
 		)
 		if err != nil {
 			return nil, err
 		}
-		popularMenus = append(popularMenus, menu)
+		if overallRank <= q.Limit {
+			menu.Rank = overallRank
+			overall[overallRank] = menu
+		}
+		if categoryRank <= q.Limit {
+			menu.Rank = categoryRank
+			ranking.CategoryRankings[menu.Category] = append(ranking.CategoryRankings[menu.Category], menu)
+		}
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return popularMenus, nil
-}
+	// 全体ランキングを順位順に並べる
+	for rank := 1; rank <= len(overall); rank++ {
+		ranking.OverallRanking = append(ranking.OverallRanking, overall[rank])
+	}
 
-// GetMenuRanking 全体ランキングとカテゴリ別ランキングを取得
-func GetMenuRanking(limit int) (*models.MenuRanking, error) {
-	ranking := &models.MenuRanking{
-		CategoryRankings: make(map[string][]models.PopularMenu),
+	// 前日のスナップショットと比較して順位変動を設定
+	if isSnapshotComparable(q) {
+		previous, err := getPreviousSnapshotRanks()
+		if err != nil {
+			return nil, err
+		}
+		if previous != nil {
+			applyRankChanges(previous[overallRankingScope], ranking.OverallRanking)
+			for category, menus := range ranking.CategoryRankings {
+				applyRankChanges(previous[categoryRankingScope(category)], menus)
+			}
+		}
 	}
 
-	// 全体ランキングを取得
-	overallRanking, err := GetPopularMenus(limit)
-	if err != nil {
-		return nil, err
+	return ranking, nil
+}
+
+// DefaultTrendingHalfLife トレンドランキングの半減期を環境変数から取得
+func DefaultTrendingHalfLife() time.Duration {
+	days := getEnvFloat("RANKING_TRENDING_HALF_LIFE_DAYS", defaultTrendingHalfLifeDays)
+	if days <= 0 {
+		days = defaultTrendingHalfLifeDays
 	}
-	ranking.OverallRanking = overallRanking
+	return time.Duration(days * float64(24*time.Hour))
+}
+
+// GetTrendingMenus 時間減衰を適用したトレンドメニューランキングを取得
+// 注文と評価は経過時間に応じて半減期halfLifeで指数的に重みが減衰する
+// 重みは加重和スコアのデフォルト設定を使う
+func GetTrendingMenus(limit int, halfLife time.Duration) ([]models.TrendingMenu, error) {
+	query := `
+		SELECT 
+			m.id as menu_id,
+			m.name,
+			m.category,
+			m.price,
+			COALESCE(o.order_count, 0) as order_count,
+			COALESCE(r.avg_rating, 0) as avg_rating,
+			COALESCE(r.total_rating, 0) as total_rating,
+			COALESCE(o.decayed_orders, 0) as decayed_orders,
+			COALESCE(r.decayed_rating, 0) as decayed_rating,
+			(COALESCE(o.decayed_orders, 0) * ? + COALESCE(r.decayed_rating, 0) * ?) as trend_score
+		FROM menus m
+		LEFT JOIN (
+			SELECT 
+				menu_id,
+				COUNT(*) as order_count,
+				SUM(POW(0.5, GREATEST(TIMESTAMPDIFF(SECOND, order_date, NOW()), 0) / ?)) as decayed_orders
+			FROM orders 
+			GROUP BY menu_id
+		) o ON m.id = o.menu_id
+		LEFT JOIN (
+			SELECT 
+				menu_id,
+				AVG(rating) as avg_rating,
+				COUNT(*) as total_rating,
+				SUM(rating * POW(0.5, GREATEST(TIMESTAMPDIFF(SECOND, created_at, NOW()), 0) / ?)) as decayed_rating
+			FROM menu_ratings 
+			GROUP BY menu_id
+		) r ON m.id = r.menu_id
+		ORDER BY trend_score DESC
+		LIMIT ?`
 
-	// カテゴリ一覧を取得
-	categoryQuery := "SELECT DISTINCT category FROM menus"
-	rows, err := DB.Query(categoryQuery)
+	weights := DefaultScoringOptions()
+	halfLifeSeconds := halfLife.Seconds()
+	rows, err := DB.Query(query, weights.OrderWeight, weights.RatingWeight, halfLifeSeconds, halfLifeSeconds, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var categories []string
+	var trendingMenus []models.TrendingMenu
 	for rows.Next() {
-		var category string
-		err := rows.Scan(&category)
+		var menu models.TrendingMenu
+		err := rows.Scan(
+			&menu.MenuID,
+			&menu.Name,
+			&menu.Category,
+			&menu.Price,
+			&menu.OrderCount,
+			&menu.AvgRating,
+			&menu.TotalRating,
+			&menu.DecayedOrders,
+			&menu.DecayedRating,
+			&menu.TrendScore,
+		)
 		if err != nil {
 			return nil, err
 		}
-		categories = append(categories, category)
+		trendingMenus = append(trendingMenus, menu)
 	}
 
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
 
-	// 各カテゴリのランキングを取得
-	for _, category := range categories {
-		categoryRanking, err := GetPopularMenusByCategory(category, limit)
-		if err != nil {
-			return nil, err
-		}
-		ranking.CategoryRankings[category] = categoryRanking
-	}
-
-	return ranking, nil
+	return trendingMenus, nil
 }
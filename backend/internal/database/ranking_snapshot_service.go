@@ -0,0 +1,226 @@
+package database
+
+import (
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// overallRankingScope 全体ランキングのスナップショットのスコープ
+const overallRankingScope = "overall"
+
+// categoryRankingScope カテゴリ別ランキングのスナップショットのスコープ
+func categoryRankingScope(category string) string {
+	return "category:" + category
+}
+
+// RankingScope 全体またはカテゴリ別ランキングのスコープ名を取得
+func RankingScope(category string) string {
+	if category == "" {
+		return overallRankingScope
+	}
+	return categoryRankingScope(category)
+}
+
+// snapshotRankingQuery スナップショットに使う取得条件（全期間・デフォルトのスコア計算）
+func snapshotRankingQuery() models.RankingQuery {
+	return models.RankingQuery{Scoring: DefaultScoringOptions()}
+}
+
+// isSnapshotComparable 取得条件がスナップショットと比較可能かどうかを返す
+func isSnapshotComparable(q models.RankingQuery) bool {
+	defaults := snapshotRankingQuery()
+	return q.Period.IsZero() &&
+		q.GroupID == 0 &&
+		q.Scoring.PriorMean == nil &&
+		q.Scoring.Method == defaults.Scoring.Method &&
+		q.Scoring.OrderWeight == defaults.Scoring.OrderWeight &&
+		q.Scoring.RatingWeight == defaults.Scoring.RatingWeight &&
+		q.Scoring.PriorWeight == defaults.Scoring.PriorWeight &&
+		q.Scoring.WilsonZ == defaults.Scoring.WilsonZ
+}
+
+// SaveRankingSnapshot 現在のランキングを指定日のスナップショットとして保存
+// 同じ日のスナップショットが既にある場合は上書きする
+func SaveRankingSnapshot(date time.Time) error {
+	source, args, err := rankingSourceSQL(snapshotRankingQuery())
+	if err != nil {
+		return err
+	}
+	query := `
+		SELECT 
+			menu_id, category, score,
+			ROW_NUMBER() OVER (ORDER BY ` + rankingOrderSQL + `) as overall_rank,
+			ROW_NUMBER() OVER (PARTITION BY category ORDER BY ` + rankingOrderSQL + `) as category_rank
+		FROM (` + source + `
+		) ranked`
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type snapshotRow struct {
+		menuID       int
+		category     string
+		score        float64
+		overallRank  int
+		categoryRank int
+	}
+	var snapshotRows []snapshotRow
+	for rows.Next() {
+		var row snapshotRow
+		if err := rows.Scan(&row.menuID, &row.category, &row.score, &row.overallRank, &row.categoryRank); err != nil {
+			return err
+		}
+		snapshotRows = append(snapshotRows, row)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	snapshotDate := date.Format(period.DateLayout)
+	if _, err := tx.Exec("DELETE FROM ranking_snapshots WHERE snapshot_date = ?", snapshotDate); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO ranking_snapshots (snapshot_date, scope, menu_id, menu_rank, score)
+		VALUES (?, ?, ?, ?, ?)`
+	stmt, err := tx.Prepare(insertQuery)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range snapshotRows {
+		if _, err := stmt.Exec(snapshotDate, overallRankingScope, row.menuID, row.overallRank, row.score); err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(snapshotDate, categoryRankingScope(row.category), row.menuID, row.categoryRank, row.score); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	InvalidateRankingCache()
+	return nil
+}
+
+// getPreviousSnapshotRanks 今日より前の最新スナップショットの順位をスコープ・メニューIDごとに取得
+// スナップショットが1件も無い場合はnilを返す
+func getPreviousSnapshotRanks() (map[string]map[int]int, error) {
+	today := period.Day(time.Now(), period.DefaultLocation()).From.Format(period.DateLayout)
+	query := `
+		SELECT scope, menu_id, menu_rank
+		FROM ranking_snapshots
+		WHERE snapshot_date = (
+			SELECT MAX(snapshot_date) FROM ranking_snapshots WHERE snapshot_date < ?
+		)`
+
+	rows, err := DB.Query(query, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ranks map[string]map[int]int
+	for rows.Next() {
+		var scope string
+		var menuID, rank int
+		if err := rows.Scan(&scope, &menuID, &rank); err != nil {
+			return nil, err
+		}
+		if ranks == nil {
+			ranks = make(map[string]map[int]int)
+		}
+		if ranks[scope] == nil {
+			ranks[scope] = make(map[int]int)
+		}
+		ranks[scope][menuID] = rank
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ranks, nil
+}
+
+// applyRankChanges 前日のスナップショットの順位と比較して順位変動を設定
+// menusのRankは設定済みであること
+func applyRankChanges(previous map[int]int, menus []models.PopularMenu) {
+	for i := range menus {
+		previousRank, ok := previous[menus[i].MenuID]
+		if !ok {
+			menus[i].RankChange = models.RankChangeNew
+			continue
+		}
+		menus[i].PreviousRank = &previousRank
+		switch {
+		case menus[i].Rank < previousRank:
+			menus[i].RankChange = models.RankChangeUp
+		case menus[i].Rank > previousRank:
+			menus[i].RankChange = models.RankChangeDown
+		default:
+			menus[i].RankChange = models.RankChangeSame
+		}
+	}
+}
+
+// GetMenuRankHistory メニューの順位の推移をスナップショットから取得
+func GetMenuRankHistory(menuID int, scope string, rng period.Range) (*models.MenuRankHistory, error) {
+	history := &models.MenuRankHistory{
+		MenuID:  menuID,
+		Scope:   scope,
+		History: []models.RankSnapshot{},
+	}
+
+	query := `
+		SELECT snapshot_date, menu_rank, score
+		FROM ranking_snapshots
+		WHERE menu_id = ? AND scope = ?`
+	args := []interface{}{menuID, scope}
+	if !rng.From.IsZero() {
+		query += " AND snapshot_date >= ?"
+		args = append(args, rng.From.Format(period.DateLayout))
+	}
+	if !rng.To.IsZero() {
+		query += " AND snapshot_date < ?"
+		args = append(args, rng.To.Format(period.DateLayout))
+	}
+	query += " ORDER BY snapshot_date"
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var snapshotDate time.Time
+		var snapshot models.RankSnapshot
+		if err := rows.Scan(&snapshotDate, &snapshot.Rank, &snapshot.Score); err != nil {
+			return nil, err
+		}
+		snapshot.Date = snapshotDate.Format(period.DateLayout)
+		history.History = append(history.History, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import "gachimatsu-backend/internal/models"
+
+// RateMenu ユーザーのメニュー評価を保存
+// 同じメニューを評価済みの場合は評価を更新し、評価日時（created_at）も新しくする
+// メニューが存在しない場合はsql.ErrNoRowsを返す
+func RateMenu(rating *models.MenuRating) error {
+	var menuID int
+	if err := DB.QueryRow("SELECT id FROM menus WHERE id = ?", rating.MenuID).Scan(&menuID); err != nil {
+		return err
+	}
+
+	// (user_id, menu_id) の一意制約で同時に評価しても1件にまとめる
+	// 更新した場合もLAST_INSERT_IDで既存の評価のIDを返す
+	result, err := DB.Exec(`
+		INSERT INTO menu_ratings (user_id, menu_id, rating, visibility, created_at) VALUES (?, ?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE 
+			id = LAST_INSERT_ID(id),
+			rating = VALUES(rating),
+			visibility = VALUES(visibility),
+			created_at = NOW()`,
+		rating.UserID, rating.MenuID, rating.Rating, rating.Visibility,
+	)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rating.ID = int(id)
+	if err := DB.QueryRow("SELECT created_at FROM menu_ratings WHERE id = ?", rating.ID).Scan(&rating.CreatedAt); err != nil {
+		return err
+	}
+
+	InvalidateRankingCache()
+	return nil
+}
+
+// getRatingVisibilities ユーザーが評価したメニューごとの評価の公開範囲を取得
+func getRatingVisibilities(userID int) ([]string, error) {
+	rows, err := DB.Query("SELECT visibility FROM menu_ratings WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var visibilities []string
+	for rows.Next() {
+		var visibility string
+		if err := rows.Scan(&visibility); err != nil {
+			return nil, err
+		}
+		visibilities = append(visibilities, visibility)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return visibilities, nil
+}
@@ -0,0 +1,310 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// recommendationReasonMenus 推薦理由に挙げる既食メニューの最大数
+const recommendationReasonMenus = 2
+
+// categoryRecommendationWeight カテゴリの好みだけに基づく推薦スコアの重み
+// 協調フィルタリングの結果より下位に並ぶよう小さくしている
+const categoryRecommendationWeight = 0.1
+
+// menuInfo 推薦計算に使うメニューの基本情報
+type menuInfo struct {
+	ID       int
+	Name     string
+	Category string
+	Price    int
+}
+
+// getMenuCatalog 全メニューの基本情報をIDごとに取得
+func getMenuCatalog() (map[int]menuInfo, error) {
+	rows, err := DB.Query("SELECT id, name, category, price FROM menus")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	catalog := make(map[int]menuInfo)
+	for rows.Next() {
+		var menu menuInfo
+		if err := rows.Scan(&menu.ID, &menu.Name, &menu.Category, &menu.Price); err != nil {
+			return nil, err
+		}
+		catalog[menu.ID] = menu
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// neutralRating 好みが中立とみなす評価（これより低い評価は「好きではない」として扱う）
+const neutralRating = 2.5
+
+// getUserMenuPreferences 注文と評価からユーザーごとのメニューへの好みの強さを取得
+// 注文回数nに対して1+ln(n)を基本とし、評価がある場合は評価に応じた係数を掛ける
+// 注文せずに評価だけしたメニューは係数だけを使う
+// 評価が1〜2のメニューは好みの強さを負の値にし、似たユーザーが低く評価したメニューを推薦しにくくする
+func getUserMenuPreferences() (map[int]map[int]float64, error) {
+	preferences := make(map[int]map[int]float64)
+
+	orderRows, err := DB.Query(`
+		SELECT user_id, menu_id, SUM(COALESCE(quantity, 1)) as order_count
+		FROM orders
+		WHERE user_id IS NOT NULL AND menu_id IS NOT NULL
+		GROUP BY user_id, menu_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer orderRows.Close()
+
+	for orderRows.Next() {
+		var userID, menuID int
+		var orderCount float64
+		if err := orderRows.Scan(&userID, &menuID, &orderCount); err != nil {
+			return nil, err
+		}
+		if preferences[userID] == nil {
+			preferences[userID] = make(map[int]float64)
+		}
+		preferences[userID][menuID] = 1 + math.Log(math.Max(orderCount, 1))
+	}
+	if err = orderRows.Err(); err != nil {
+		return nil, err
+	}
+
+	ratingRows, err := DB.Query(`
+		SELECT user_id, menu_id, AVG(rating) as avg_rating
+		FROM menu_ratings
+		WHERE user_id IS NOT NULL AND menu_id IS NOT NULL
+		GROUP BY user_id, menu_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer ratingRows.Close()
+
+	for ratingRows.Next() {
+		var userID, menuID int
+		var rating float64
+		if err := ratingRows.Scan(&userID, &menuID, &rating); err != nil {
+			return nil, err
+		}
+		if preferences[userID] == nil {
+			preferences[userID] = make(map[int]float64)
+		}
+		base, ok := preferences[userID][menuID]
+		if !ok {
+			base = 1
+		}
+		preferences[userID][menuID] = base * ratingFactor(rating)
+	}
+	if err = ratingRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return preferences, nil
+}
+
+// ratingFactor 評価を好みの強さの係数に変換
+// 評価5で5/3、評価4で1、評価2.5で0、評価1で-1になる
+func ratingFactor(rating float64) float64 {
+	return (rating - neutralRating) * 2 / 3
+}
+
+// GetRecommendations ユーザーへのおすすめメニューを取得
+// 未制覇メニューをアイテムベースの協調フィルタリングとカテゴリの好みでスコア付けし、
+// 注文・評価の無いユーザーや候補が足りない場合は人気メニューで補う
+func GetRecommendations(userID int, limit int) ([]models.Recommendation, error) {
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return nil, err
+	}
+	preferences, err := getUserMenuPreferences()
+	if err != nil {
+		return nil, err
+	}
+
+	target := preferences[userID]
+	candidates := make(map[int]*models.Recommendation)
+
+	if len(target) > 0 {
+		scoreCollaborative(userID, target, preferences, catalog, candidates)
+		scoreCategoryPreference(target, catalog, candidates)
+	}
+
+	recommendations := make([]models.Recommendation, 0, len(candidates))
+	for _, candidate := range candidates {
+		recommendations = append(recommendations, *candidate)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].MenuID < recommendations[j].MenuID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	// 候補が足りない場合は人気メニューのうち未制覇のもので補う
+	if len(recommendations) < limit {
+		popularMenus, err := GetPopularMenus(models.RankingQuery{
+			Limit:   len(catalog),
+			Scoring: DefaultScoringOptions(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, menu := range popularMenus {
+			if len(recommendations) >= limit {
+				break
+			}
+			if _, eaten := target[menu.MenuID]; eaten {
+				continue
+			}
+			if _, added := candidates[menu.MenuID]; added {
+				continue
+			}
+			recommendations = append(recommendations, models.Recommendation{
+				MenuID:   menu.MenuID,
+				Name:     menu.Name,
+				Category: menu.Category,
+				Price:    menu.Price,
+				Score:    menu.Score,
+				Source:   models.RecommendationPopular,
+				Reason:   fmt.Sprintf("人気ランキング%d位のメニューです", menu.Rank),
+			})
+		}
+	}
+
+	return recommendations, nil
+}
+
+// scoreCollaborative アイテムベースの協調フィルタリングで未制覇メニューをスコア付け
+// メニュー間の類似度はユーザーごとの好みの強さを並べたベクトルのコサイン類似度
+func scoreCollaborative(userID int, target map[int]float64, preferences map[int]map[int]float64, catalog map[int]menuInfo, candidates map[int]*models.Recommendation) {
+	// メニューごとのベクトルのノルムと、候補メニューと既食メニューの内積を集計
+	norms := make(map[int]float64)
+	dots := make(map[int]map[int]float64)
+	for otherID, otherPrefs := range preferences {
+		for menuID, value := range otherPrefs {
+			norms[menuID] += value * value
+		}
+		if otherID == userID {
+			continue
+		}
+		for candidateID, candidateValue := range otherPrefs {
+			if _, eaten := target[candidateID]; eaten {
+				continue
+			}
+			for eatenID := range target {
+				eatenValue, ok := otherPrefs[eatenID]
+				if !ok {
+					continue
+				}
+				if dots[candidateID] == nil {
+					dots[candidateID] = make(map[int]float64)
+				}
+				dots[candidateID][eatenID] += candidateValue * eatenValue
+			}
+		}
+	}
+
+	for candidateID, eatenDots := range dots {
+		menu, ok := catalog[candidateID]
+		if !ok {
+			continue
+		}
+
+		type contribution struct {
+			menuID int
+			value  float64
+		}
+		var contributions []contribution
+		var score float64
+		for eatenID, dot := range eatenDots {
+			similarity := dot / math.Sqrt(norms[candidateID]*norms[eatenID])
+			score += similarity * target[eatenID]
+			// 推薦理由には好きなメニューからの正の寄与だけを挙げる
+			if value := similarity * target[eatenID]; value > 0 {
+				contributions = append(contributions, contribution{eatenID, value})
+			}
+		}
+		// 低く評価したメニューとの類似度が勝る候補は推薦しない
+		if score <= 0 || len(contributions) == 0 {
+			continue
+		}
+		sort.Slice(contributions, func(i, j int) bool {
+			return contributions[i].value > contributions[j].value
+		})
+
+		recommendation := &models.Recommendation{
+			MenuID:   menu.ID,
+			Name:     menu.Name,
+			Category: menu.Category,
+			Price:    menu.Price,
+			Score:    score,
+			Source:   models.RecommendationCollaborative,
+		}
+		var reasonNames []string
+		for i := 0; i < len(contributions) && i < recommendationReasonMenus; i++ {
+			recommendation.BasedOnMenuIDs = append(recommendation.BasedOnMenuIDs, contributions[i].menuID)
+			reasonNames = append(reasonNames, "「"+catalog[contributions[i].menuID].Name+"」")
+		}
+		recommendation.Reason = fmt.Sprintf("%sが好きな人は「%s」も好んでいます", strings.Join(reasonNames, "と"), menu.Name)
+		candidates[candidateID] = recommendation
+	}
+}
+
+// scoreCategoryPreference よく食べるカテゴリに応じて未制覇メニューのスコアを補正
+// 協調フィルタリングの候補はカテゴリの割合だけスコアを上げ、それ以外の未制覇メニューも候補に加える
+func scoreCategoryPreference(target map[int]float64, catalog map[int]menuInfo, candidates map[int]*models.Recommendation) {
+	categoryShares := make(map[string]float64)
+	var total float64
+	for menuID, value := range target {
+		// 好きではないメニューはカテゴリの好みに数えない
+		if value <= 0 {
+			continue
+		}
+		if menu, ok := catalog[menuID]; ok {
+			categoryShares[menu.Category] += value
+			total += value
+		}
+	}
+	if total == 0 {
+		return
+	}
+	for category := range categoryShares {
+		categoryShares[category] /= total
+	}
+
+	for menuID, menu := range catalog {
+		share := categoryShares[menu.Category]
+		if _, eaten := target[menuID]; eaten || share == 0 {
+			continue
+		}
+		if candidate, ok := candidates[menuID]; ok {
+			candidate.Score *= 1 + share
+			continue
+		}
+		candidates[menuID] = &models.Recommendation{
+			MenuID:   menu.ID,
+			Name:     menu.Name,
+			Category: menu.Category,
+			Price:    menu.Price,
+			Score:    share * categoryRecommendationWeight,
+			Source:   models.RecommendationCategory,
+			Reason:   fmt.Sprintf("よく食べる「%s」カテゴリの未制覇メニューです", menu.Category),
+		}
+	}
+}
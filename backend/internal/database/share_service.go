@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// GetOrderOwner 食事記録の持ち主のユーザーIDを取得
+func GetOrderOwner(orderID int) (int, error) {
+	var ownerID int
+	err := DB.QueryRow("SELECT user_id FROM orders WHERE id = ?", orderID).Scan(&ownerID)
+	return ownerID, err
+}
+
+// CreateShareLink 共有リンクを作成
+func CreateShareLink(link *models.ShareLink) error {
+	result, err := DB.Exec(
+		"INSERT INTO share_links (user_id, target, order_id, expires_at, created_at) VALUES (?, ?, ?, ?, NOW())",
+		link.UserID, link.Target, link.OrderID, link.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	created, err := GetShareLink(int(id))
+	if err != nil {
+		return err
+	}
+	*link = *created
+	return nil
+}
+
+// GetShareLink 有効な共有リンクを取得
+// リンクが存在しないか、期限切れ・無効化済みの場合はsql.ErrNoRowsを返す
+func GetShareLink(id int) (*models.ShareLink, error) {
+	query := `
+		SELECT id, user_id, target, order_id, expires_at, created_at
+		FROM share_links
+		WHERE id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+	`
+	return scanShareLink(DB.QueryRow(query, id))
+}
+
+// GetShareLinks ユーザーの有効な共有リンクを新しい順に取得
+func GetShareLinks(userID int) ([]models.ShareLink, error) {
+	query := `
+		SELECT id, user_id, target, order_id, expires_at, created_at
+		FROM share_links
+		WHERE user_id = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := DB.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []models.ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// scanShareLink 共有リンクの行を読み込む
+func scanShareLink(row interface{ Scan(...interface{}) error }) (*models.ShareLink, error) {
+	var link models.ShareLink
+	var orderID sql.NullInt64
+	var expiresAt sql.NullTime
+	err := row.Scan(&link.ID, &link.UserID, &link.Target, &orderID, &expiresAt, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if orderID.Valid {
+		id := int(orderID.Int64)
+		link.OrderID = &id
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+	return &link, nil
+}
+
+// RevokeShareLink 共有リンクを無効化
+// ユーザーの有効な共有リンクが存在しない場合はsql.ErrNoRowsを返す
+func RevokeShareLink(userID, id int) error {
+	result, err := DB.Exec(
+		"UPDATE share_links SET revoked_at = NOW() WHERE id = ? AND user_id = ? AND revoked_at IS NULL",
+		id, userID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetSharedOrder 共有リンクで公開する食事記録を取得
+// 食事記録が削除されている場合はsql.ErrNoRowsを返す
+func GetSharedOrder(orderID int) (*models.SharedOrder, error) {
+	query := `
+		SELECT 
+			o.menu_id,
+			COALESCE(o.menu_name, m.name, '') as menu_name,
+			COALESCE(o.menu_category, m.category, '') as menu_category,
+			COALESCE(m.image_url, '') as image_url,
+			COALESCE(o.quantity, 1) as quantity,
+			o.order_date
+		FROM orders o
+		LEFT JOIN menus m ON m.id = o.menu_id
+		WHERE o.id = ?
+	`
+
+	var order models.SharedOrder
+	err := DB.QueryRow(query, orderID).Scan(
+		&order.MenuID,
+		&order.MenuName,
+		&order.MenuCategory,
+		&order.MenuImageURL,
+		&order.Quantity,
+		&order.OrderDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
@@ -0,0 +1,270 @@
+package database
+
+import (
+	"math"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// minCoRatingUsers 評価の相関を計算するのに必要な両方を評価したユーザー数
+const minCoRatingUsers = 2
+
+// DefaultSimilarityWeights 環境変数から類似度の各要素の重みを取得
+func DefaultSimilarityWeights() models.SimilarityWeights {
+	return models.SimilarityWeights{
+		CoOrder:  getEnvFloat("SIMILARITY_WEIGHT_CO_ORDER", 0.4),
+		Category: getEnvFloat("SIMILARITY_WEIGHT_CATEGORY", 0.2),
+		Price:    getEnvFloat("SIMILARITY_WEIGHT_PRICE", 0.2),
+		Rating:   getEnvFloat("SIMILARITY_WEIGHT_RATING", 0.2),
+	}
+}
+
+// similarityComponents 2つのメニュー間の類似度の各要素
+type similarityComponents struct {
+	coOrder  float64
+	category float64
+	price    float64
+	rating   float64
+}
+
+// score 重み付きの類似度を計算
+func (c similarityComponents) score(weights models.SimilarityWeights) float64 {
+	return c.coOrder*weights.CoOrder + c.category*weights.Category + c.price*weights.Price + c.rating*weights.Rating
+}
+
+// RefreshMenuSimilarities 全メニューの組み合わせの類似度を計算してテーブルを更新
+// 取得時に任意の重みで並べ替えられるよう、上位だけでなく全ての組み合わせの各要素を保存する
+func RefreshMenuSimilarities() error {
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return err
+	}
+
+	// メニューごとに注文したユーザーの集合を取得
+	orderers := make(map[int]map[int]bool)
+	rows, err := DB.Query(`
+		SELECT DISTINCT menu_id, user_id
+		FROM orders
+		WHERE user_id IS NOT NULL AND menu_id IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var menuID, userID int
+		if err := rows.Scan(&menuID, &userID); err != nil {
+			return err
+		}
+		if orderers[menuID] == nil {
+			orderers[menuID] = make(map[int]bool)
+		}
+		orderers[menuID][userID] = true
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	// メニューごとにユーザーの評価を取得
+	ratings := make(map[int]map[int]float64)
+	ratingRows, err := DB.Query(`
+		SELECT menu_id, user_id, AVG(rating)
+		FROM menu_ratings
+		WHERE user_id IS NOT NULL AND menu_id IS NOT NULL
+		GROUP BY menu_id, user_id`)
+	if err != nil {
+		return err
+	}
+	defer ratingRows.Close()
+	for ratingRows.Next() {
+		var menuID, userID int
+		var rating float64
+		if err := ratingRows.Scan(&menuID, &userID, &rating); err != nil {
+			return err
+		}
+		if ratings[menuID] == nil {
+			ratings[menuID] = make(map[int]float64)
+		}
+		ratings[menuID][userID] = rating
+	}
+	if err = ratingRows.Err(); err != nil {
+		return err
+	}
+
+	weights := DefaultSimilarityWeights()
+
+	type similarityRow struct {
+		menuID        int
+		similarMenuID int
+		score         float64
+		components    similarityComponents
+	}
+	var similarityRows []similarityRow
+	for _, menu := range catalog {
+		for _, other := range catalog {
+			if other.ID == menu.ID {
+				continue
+			}
+			components := similarityComponents{
+				coOrder: jaccard(orderers[menu.ID], orderers[other.ID]),
+				price:   priceProximity(menu.Price, other.Price),
+				rating:  ratingCorrelation(ratings[menu.ID], ratings[other.ID]),
+			}
+			if menu.Category == other.Category {
+				components.category = 1
+			}
+			similarityRows = append(similarityRows, similarityRow{
+				menuID:        menu.ID,
+				similarMenuID: other.ID,
+				score:         components.score(weights),
+				components:    components,
+			})
+		}
+	}
+
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM menu_similarities"); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO menu_similarities 
+			(menu_id, similar_menu_id, score, co_order_score, category_score, price_score, rating_score, computed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW())`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range similarityRows {
+		_, err := stmt.Exec(
+			row.menuID,
+			row.similarMenuID,
+			row.score,
+			row.components.coOrder,
+			row.components.category,
+			row.components.price,
+			row.components.rating,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// jaccard 2つの集合のJaccard係数を計算
+func jaccard(a, b map[int]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for id := range a {
+		if b[id] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// priceProximity 価格の近さを0〜1で計算（同じ価格なら1）
+func priceProximity(a, b int) float64 {
+	maxPrice := math.Max(float64(a), float64(b))
+	if maxPrice <= 0 {
+		return 1
+	}
+	return 1 - math.Abs(float64(a-b))/maxPrice
+}
+
+// ratingCorrelation 両方を評価したユーザーの評価のピアソン相関を0〜1に変換して計算
+// 両方を評価したユーザーが少ない場合や分散が無い場合は0とする
+func ratingCorrelation(a, b map[int]float64) float64 {
+	var xs, ys []float64
+	for userID, x := range a {
+		if y, ok := b[userID]; ok {
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+	if len(xs) < minCoRatingUsers {
+		return 0
+	}
+
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return (cov/math.Sqrt(varX*varY) + 1) / 2
+}
+
+// GetSimilarMenus 類似メニューを事前計算済みのテーブルから取得
+// 類似度は指定された重みで各要素から再計算して並べる
+func GetSimilarMenus(menuID int, weights models.SimilarityWeights, limit int) ([]models.SimilarMenu, error) {
+	query := `
+		SELECT 
+			s.similar_menu_id,
+			m.name,
+			m.category,
+			m.price,
+			(s.co_order_score * ? + s.category_score * ? + s.price_score * ? + s.rating_score * ?) as weighted_score,
+			s.co_order_score,
+			s.category_score,
+			s.price_score,
+			s.rating_score
+		FROM menu_similarities s
+		JOIN menus m ON m.id = s.similar_menu_id
+		WHERE s.menu_id = ?
+		ORDER BY weighted_score DESC, s.similar_menu_id
+		LIMIT ?`
+
+	rows, err := DB.Query(query, weights.CoOrder, weights.Category, weights.Price, weights.Rating, menuID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	similarMenus := []models.SimilarMenu{}
+	for rows.Next() {
+		var menu models.SimilarMenu
+		err := rows.Scan(
+			&menu.MenuID,
+			&menu.Name,
+			&menu.Category,
+			&menu.Price,
+			&menu.Score,
+			&menu.CoOrderScore,
+			&menu.CategoryScore,
+			&menu.PriceScore,
+			&menu.RatingScore,
+		)
+		if err != nil {
+			return nil, err
+		}
+		similarMenus = append(similarMenus, menu)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return similarMenus, nil
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// maxSpendingBuckets 支出の推移で1回に返す期間の最大数
+const maxSpendingBuckets = 1000
+
+// ErrTooManySpendingBuckets 集計期間が長すぎて期間の数が上限を超える場合のエラー
+var ErrTooManySpendingBuckets = errors.New("too many spending buckets")
+
+// GetSpendingSeries ユーザーの支出を日・週・月ごとに集計して取得
+// 集計期間に制限がある場合は注文の無い期間も0として含める
+// 期間の数がmaxSpendingBucketsを超える場合はErrTooManySpendingBucketsを返す
+func GetSpendingSeries(userID int, interval string, rng period.Range, loc *time.Location) (*models.SpendingSeries, error) {
+	if _, err := period.Of(interval, time.Now(), loc); err != nil {
+		return nil, err
+	}
+	bucket := func(t time.Time) period.Range {
+		rng, _ := period.Of(interval, t, loc)
+		return rng
+	}
+
+	orders, err := getUserOrders(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.SpendingSeries{
+		UserID:   userID,
+		Interval: interval,
+		Points:   []models.SpendingPoint{},
+	}
+
+	var filtered []userOrder
+	for _, order := range orders {
+		if !rng.From.IsZero() && order.OrderDate.Before(rng.From) {
+			continue
+		}
+		if !rng.To.IsZero() && !order.OrderDate.Before(rng.To) {
+			continue
+		}
+		filtered = append(filtered, order)
+	}
+
+	// 集計する期間の範囲を決める（制限が無い側は最初・最後の注文まで）
+	from, to := rng.From, rng.To
+	if len(filtered) > 0 {
+		if from.IsZero() {
+			from = filtered[0].OrderDate
+		}
+		if to.IsZero() {
+			to = filtered[len(filtered)-1].OrderDate.Add(time.Nanosecond)
+		}
+	}
+	if from.IsZero() || to.IsZero() {
+		return series, nil
+	}
+
+	if countBuckets(interval, bucket(from).From, to) > maxSpendingBuckets {
+		return nil, ErrTooManySpendingBuckets
+	}
+
+	// 空の期間も含めて集計用のバケットを用意
+	indexes := make(map[time.Time]int)
+	for start := bucket(from).From; start.Before(to); start = bucket(start).To {
+		indexes[start] = len(series.Points)
+		series.Points = append(series.Points, models.SpendingPoint{PeriodStart: start.Format(period.DateLayout)})
+	}
+
+	for _, v := range groupVisits(filtered) {
+		index := indexes[bucket(v.At).From]
+		series.Points[index].Visits++
+	}
+	for _, order := range filtered {
+		index := indexes[bucket(order.OrderDate).From]
+		series.Points[index].Amount += order.amount()
+		series.Points[index].Orders++
+		series.Total += order.amount()
+	}
+
+	return series, nil
+}
+
+// countBuckets fromからtoまでの期間の数を見積もる（バケットを作る前に上限を確認するため）
+func countBuckets(interval string, from, to time.Time) int {
+	switch interval {
+	case period.Monthly:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	case period.Weekly:
+		return int(to.Sub(from).Hours()/(24*7)) + 1
+	default:
+		return int(to.Sub(from).Hours()/24) + 1
+	}
+}
+
+// SetMonthlyBudget ユーザーの月間予算を設定
+func SetMonthlyBudget(userID int, monthlyBudget int) error {
+	query := `
+		INSERT INTO user_budgets (user_id, monthly_budget) 
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE monthly_budget = VALUES(monthly_budget)`
+
+	_, err := DB.Exec(query, userID, monthlyBudget)
+	return err
+}
+
+// getMonthlyBudget ユーザーの月間予算を取得（未設定の場合はnil）
+func getMonthlyBudget(userID int) (*int, error) {
+	var monthlyBudget int
+	err := DB.QueryRow("SELECT monthly_budget FROM user_budgets WHERE user_id = ?", userID).Scan(&monthlyBudget)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &monthlyBudget, nil
+}
+
+// GetBudgetStatus monthを含む月の予算に対する支出状況を取得
+// 当月は経過日数あたりの支出から月末の支出見込みを計算し、過去の月は実績をそのまま見込みとする
+func GetBudgetStatus(userID int, month time.Time, loc *time.Location) (*models.BudgetStatus, error) {
+	monthRange := period.Month(month, loc)
+	status := &models.BudgetStatus{
+		UserID: userID,
+		Month:  monthRange.From.Format("2006-01"),
+	}
+
+	orders, err := getUserOrders(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		if !order.OrderDate.Before(monthRange.From) && order.OrderDate.Before(monthRange.To) {
+			status.Spent += order.amount()
+		}
+	}
+
+	now := time.Now()
+	switch {
+	case now.Before(monthRange.From):
+		status.ProjectedSpend = status.Spent
+	case now.Before(monthRange.To):
+		daysInMonth := daysBetween(monthRange.From, monthRange.To)
+		elapsedDays := daysBetween(monthRange.From, period.Day(now, loc).From) + 1
+		status.ProjectedSpend = int(math.Round(float64(status.Spent) / float64(elapsedDays) * float64(daysInMonth)))
+	default:
+		status.ProjectedSpend = status.Spent
+	}
+
+	status.MonthlyBudget, err = getMonthlyBudget(userID)
+	if err != nil {
+		return nil, err
+	}
+	if status.MonthlyBudget != nil {
+		remaining := *status.MonthlyBudget - status.Spent
+		status.Remaining = &remaining
+		status.Warning = status.ProjectedSpend > *status.MonthlyBudget
+	}
+
+	return status, nil
+}
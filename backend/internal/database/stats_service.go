@@ -1,6 +1,8 @@
 package database
 
 import (
+	"time"
+
 	"gachimatsu-backend/internal/models"
 )
 
@@ -50,3 +52,104 @@ func GetStats() (*models.Stats, error) {
 
 	return stats, nil
 }
+
+// GetPriceChangeReport 指定日時以降のメニュー価格変更と価格変動を取得
+// 指定日時点の価格は、それ以降の最初の変更の変更前価格（変更が無ければ現在の価格）とする
+func GetPriceChangeReport(since time.Time) (*models.PriceChangeReport, error) {
+	report := &models.PriceChangeReport{
+		Since:         since,
+		CategoryDrift: make(map[string]models.CategoryPriceDrift),
+		Changes:       []models.PriceChange{},
+	}
+
+	catalog, err := getMenuCatalog()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT h.id, h.menu_id, m.name, m.category, h.old_price, h.new_price, h.changed_at
+		FROM menu_price_history h
+		JOIN menus m ON m.id = h.menu_id
+		WHERE h.changed_at >= ?
+		ORDER BY h.changed_at, h.id
+	`
+	rows, err := DB.Query(query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var change models.PriceChange
+		err := rows.Scan(
+			&change.ID,
+			&change.MenuID,
+			&change.MenuName,
+			&change.Category,
+			&change.OldPrice,
+			&change.NewPrice,
+			&change.ChangedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		report.Changes = append(report.Changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 指定日時点の価格を求める
+	priceAtSince := make(map[int]int)
+	for _, change := range report.Changes {
+		if _, ok := priceAtSince[change.MenuID]; !ok {
+			priceAtSince[change.MenuID] = change.OldPrice
+		}
+	}
+
+	type categoryTotals struct {
+		menus        int
+		current      int
+		atSince      int
+		changedMenus int
+	}
+	totals := make(map[string]*categoryTotals)
+	var current, atSince int
+	for _, menu := range catalog {
+		price, changed := priceAtSince[menu.ID]
+		if !changed {
+			price = menu.Price
+		}
+		if totals[menu.Category] == nil {
+			totals[menu.Category] = &categoryTotals{}
+		}
+		t := totals[menu.Category]
+		t.menus++
+		t.current += menu.Price
+		t.atSince += price
+		if changed {
+			t.changedMenus++
+		}
+		current += menu.Price
+		atSince += price
+	}
+
+	if len(catalog) > 0 {
+		report.AveragePrice = float64(current) / float64(len(catalog))
+		report.AveragePriceAtSince = float64(atSince) / float64(len(catalog))
+		report.AverageDrift = report.AveragePrice - report.AveragePriceAtSince
+	}
+	for category, t := range totals {
+		drift := models.CategoryPriceDrift{
+			AveragePrice:        float64(t.current) / float64(t.menus),
+			AveragePriceAtSince: float64(t.atSince) / float64(t.menus),
+			ChangedMenus:        t.changedMenus,
+		}
+		drift.AverageDrift = drift.AveragePrice - drift.AveragePriceAtSince
+		report.CategoryDrift[category] = drift
+	}
+
+	return report, nil
+}
@@ -3,13 +3,15 @@ package database
 import (
 	"database/sql"
 	"strings"
+	"time"
 	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
 )
 
 // GetAllUsers 全ユーザーを取得
 func GetAllUsers() ([]models.User, error) {
 	query := `
-		SELECT id, name, email, created_at, updated_at 
+		SELECT id, name, email, time_zone, email_verified_at, created_at, updated_at 
 		FROM users 
 		ORDER BY id
 	`
@@ -23,16 +25,22 @@ func GetAllUsers() ([]models.User, error) {
 	var users []models.User
 	for rows.Next() {
 		var user models.User
+		var emailVerifiedAt sql.NullTime
 		err := rows.Scan(
 			&user.ID,
 			&user.Name,
 			&user.Email,
+			&user.TimeZone,
+			&emailVerifiedAt,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if emailVerifiedAt.Valid {
+			user.EmailVerifiedAt = &emailVerifiedAt.Time
+		}
 		users = append(users, user)
 	}
 
@@ -46,16 +54,35 @@ func GetAllUsers() ([]models.User, error) {
 // GetUserByID 特定のユーザーを取得
 func GetUserByID(id int) (*models.User, error) {
 	query := `
-		SELECT id, name, email, created_at, updated_at 
+		SELECT id, name, email, time_zone, email_verified_at, created_at, updated_at 
 		FROM users 
 		WHERE id = ?
 	`
 
+	return scanUser(DB.QueryRow(query, id))
+}
+
+// GetUserByEmail メールアドレスからユーザーを取得
+func GetUserByEmail(email string) (*models.User, error) {
+	query := `
+		SELECT id, name, email, time_zone, email_verified_at, created_at, updated_at 
+		FROM users 
+		WHERE email = ?
+	`
+
+	return scanUser(DB.QueryRow(query, email))
+}
+
+// scanUser クエリ結果をユーザーに変換
+func scanUser(row *sql.Row) (*models.User, error) {
 	var user models.User
-	err := DB.QueryRow(query, id).Scan(
+	var emailVerifiedAt sql.NullTime
+	err := row.Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
+		&user.TimeZone,
+		&emailVerifiedAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -63,6 +90,9 @@ func GetUserByID(id int) (*models.User, error) {
 	if err != nil {
 		return nil, err
 	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
 
 	return &user, nil
 }
@@ -88,6 +118,27 @@ func CreateUser(user *models.User) error {
 	return nil
 }
 
+// getUserLocation ユーザーのタイムゾーンを取得（不明な場合はデフォルトのタイムゾーン）
+func getUserLocation(userID int) (*time.Location, error) {
+	var timeZone string
+	err := DB.QueryRow("SELECT time_zone FROM users WHERE id = ?", userID).Scan(&timeZone)
+	if err != nil {
+		return nil, err
+	}
+	if loc, err := time.LoadLocation(timeZone); err == nil && timeZone != "" {
+		return loc, nil
+	}
+	return period.DefaultLocation(), nil
+}
+
+// UpdateUserTimeZone ユーザーのタイムゾーンを更新
+func UpdateUserTimeZone(id int, timeZone string) error {
+	query := `UPDATE users SET time_zone = ? WHERE id = ?`
+
+	_, err := DB.Exec(query, timeZone, id)
+	return err
+}
+
 // DeleteUserByID 特定のユーザーを削除
 func DeleteUserByID(id int) error {
 	query := `DELETE FROM users WHERE id = ?`
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"database/sql"
+
+	"gachimatsu-backend/internal/models"
+)
+
+// GetUserSettings ユーザー設定を取得
+// 設定が保存されていない場合はデフォルト値を返す
+func GetUserSettings(userID int) (*models.UserSettings, error) {
+	settings := models.UserSettings{UserID: userID}
+	err := DB.QueryRow(
+		"SELECT leaderboard_opt_out, weekly_digest, public_badge, mail_language FROM user_settings WHERE user_id = ?",
+		userID,
+	).Scan(&settings.LeaderboardOptOut, &settings.WeeklyDigest, &settings.PublicBadge, &settings.MailLanguage)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateUserSettings ユーザー設定を更新
+func UpdateUserSettings(userID int, req models.UpdateUserSettingsRequest) (*models.UserSettings, error) {
+	settings, err := GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.LeaderboardOptOut != nil {
+		settings.LeaderboardOptOut = *req.LeaderboardOptOut
+	}
+	if req.WeeklyDigest != nil {
+		settings.WeeklyDigest = *req.WeeklyDigest
+	}
+	if req.PublicBadge != nil {
+		settings.PublicBadge = *req.PublicBadge
+	}
+	if req.MailLanguage != nil {
+		settings.MailLanguage = *req.MailLanguage
+	}
+
+	query := `
+		INSERT INTO user_settings (user_id, leaderboard_opt_out, weekly_digest, public_badge, mail_language) VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE 
+			leaderboard_opt_out = VALUES(leaderboard_opt_out),
+			weekly_digest = VALUES(weekly_digest),
+			public_badge = VALUES(public_badge),
+			mail_language = VALUES(mail_language)
+	`
+	if _, err := DB.Exec(query, userID, settings.LeaderboardOptOut, settings.WeeklyDigest, settings.PublicBadge, settings.MailLanguage); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
@@ -0,0 +1,209 @@
+package database
+
+import (
+	"sort"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// userOrder 集計に使うユーザーの注文（Priceは注文時点の単価）
+type userOrder struct {
+	ID        int
+	MenuID    int
+	MenuName  string
+	Category  string
+	Price     int
+	Quantity  int
+	OrderDate time.Time
+	// Visibility 注文の公開範囲
+	Visibility string
+}
+
+// amount 注文の金額
+func (o userOrder) amount() int {
+	return o.Price * o.Quantity
+}
+
+// getUserOrders ユーザーの注文を古い順に取得
+// 単価・メニュー名・カテゴリは注文時点のスナップショットを使い、
+// スナップショットの無い古い注文のみ現在のメニュー情報で補う
+func getUserOrders(userID int) ([]userOrder, error) {
+	return getVisibleUserOrders(userID, userID)
+}
+
+// getVisibleUserOrders ユーザーの注文のうちviewerIDのユーザーから見えるものを古い順に取得
+func getVisibleUserOrders(userID, viewerID int) ([]userOrder, error) {
+	visible, visibleArgs := visibilityCondition("o", viewerID)
+	query := `
+		SELECT 
+			o.id,
+			o.menu_id,
+			COALESCE(o.menu_name, m.name, '') as menu_name,
+			COALESCE(o.menu_category, m.category, '') as menu_category,
+			COALESCE(o.unit_price, m.price, 0) as unit_price,
+			COALESCE(o.quantity, 1) as quantity,
+			o.order_date,
+			o.visibility
+		FROM orders o
+		LEFT JOIN menus m ON m.id = o.menu_id
+		WHERE o.user_id = ? AND ` + visible + `
+		ORDER BY o.order_date, o.id`
+
+	rows, err := DB.Query(query, append([]interface{}{userID}, visibleArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []userOrder
+	for rows.Next() {
+		var order userOrder
+		err := rows.Scan(
+			&order.ID,
+			&order.MenuID,
+			&order.MenuName,
+			&order.Category,
+			&order.Price,
+			&order.Quantity,
+			&order.OrderDate,
+			&order.Visibility,
+		)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// visit 1回の来店（同じ日時に記録された注文をまとめたもの）
+type visit struct {
+	At     time.Time
+	Amount int
+}
+
+// groupVisits 注文を来店ごとにまとめる（ordersは古い順であること）
+func groupVisits(orders []userOrder) []visit {
+	var visits []visit
+	for _, order := range orders {
+		if len(visits) > 0 && visits[len(visits)-1].At.Equal(order.OrderDate) {
+			visits[len(visits)-1].Amount += order.amount()
+			continue
+		}
+		visits = append(visits, visit{At: order.OrderDate, Amount: order.amount()})
+	}
+	return visits
+}
+
+// timeOfDay 時刻から時間帯の区分を取得
+func timeOfDay(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 10:
+		return models.TimeOfDayMorning
+	case hour >= 10 && hour < 15:
+		return models.TimeOfDayLunch
+	case hour >= 15 && hour < 17:
+		return models.TimeOfDayAfternoon
+	case hour >= 17 && hour < 22:
+		return models.TimeOfDayDinner
+	default:
+		return models.TimeOfDayLateNight
+	}
+}
+
+// GetUserStats ユーザーごとの統計情報を取得
+// viewerIDのユーザーから見える注文だけを集計し、曜日・時間帯・来店間隔はlocのタイムゾーンで集計する
+func GetUserStats(userID, viewerID int, loc *time.Location) (*models.UserStats, error) {
+	orders, err := getVisibleUserOrders(userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &models.UserStats{UserID: userID}
+	if len(orders) == 0 {
+		return stats, nil
+	}
+
+	menuCounts := make(map[int]*models.FavoriteMenu)
+	categoryCounts := make(map[string]int)
+	for _, order := range orders {
+		stats.TotalOrders++
+		stats.TotalSpent += order.amount()
+		if menuCounts[order.MenuID] == nil {
+			menuCounts[order.MenuID] = &models.FavoriteMenu{MenuID: order.MenuID, Name: order.MenuName}
+		}
+		menuCounts[order.MenuID].Count += order.Quantity
+		categoryCounts[order.Category] += order.Quantity
+	}
+
+	for _, menu := range menuCounts {
+		if stats.FavoriteMenu == nil || menu.Count > stats.FavoriteMenu.Count ||
+			(menu.Count == stats.FavoriteMenu.Count && menu.MenuID < stats.FavoriteMenu.MenuID) {
+			stats.FavoriteMenu = menu
+		}
+	}
+	stats.FavoriteCategory = &models.FavoriteCategory{Category: mostFrequent(categoryCounts)}
+	stats.FavoriteCategory.Count = categoryCounts[stats.FavoriteCategory.Category]
+
+	visits := groupVisits(orders)
+	stats.TotalVisits = len(visits)
+	stats.AverageSpendPerVisit = float64(stats.TotalSpent) / float64(len(visits))
+
+	weekdayCounts := make(map[string]int)
+	timeOfDayCounts := make(map[string]int)
+	for _, v := range visits {
+		local := v.At.In(loc)
+		weekdayCounts[local.Weekday().String()]++
+		timeOfDayCounts[timeOfDay(local)]++
+	}
+	stats.MostFrequentWeekday = mostFrequent(weekdayCounts)
+	stats.MostFrequentTimeOfDay = mostFrequent(timeOfDayCounts)
+
+	first := visits[0].At
+	last := visits[len(visits)-1].At
+	stats.FirstVisitAt = &first
+	stats.LastVisitAt = &last
+
+	// 来店日の間隔が最も長い区間を求める
+	for i := 1; i < len(visits); i++ {
+		from := period.Day(visits[i-1].At, loc).From
+		to := period.Day(visits[i].At, loc).From
+		days := daysBetween(from, to)
+		if days > 0 && (stats.LongestGap == nil || days > stats.LongestGap.Days) {
+			stats.LongestGap = &models.VisitGap{Days: days, From: visits[i-1].At, To: visits[i].At}
+		}
+	}
+
+	return stats, nil
+}
+
+// daysBetween 2つの日付（0時）の間の日数を取得（夏時間の切り替えがあっても日単位で数える）
+func daysBetween(from, to time.Time) int {
+	fromDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDate := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toDate.Sub(fromDate).Hours() / 24)
+}
+
+// mostFrequent 出現回数が最も多いキーを取得（同数の場合は辞書順で先のもの）
+func mostFrequent(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	best := ""
+	for _, key := range keys {
+		if best == "" || counts[key] > counts[best] {
+			best = key
+		}
+	}
+	return best
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// yearInReviewTopMenuCount 1年のまとめに載せるよく注文したメニューの数
+const yearInReviewTopMenuCount = 5
+
+// GetYearInReview ユーザーの指定年の活動のまとめを取得
+// viewerIDのユーザーから見える食事記録だけを集計し、年の区切り・時間帯・連続記録はlocのタイムゾーンで集計する
+func GetYearInReview(user *models.User, viewerID, year int, loc *time.Location) (*models.YearInReview, error) {
+	orders, err := getVisibleUserOrders(user.ID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(1, 0, 0)
+
+	review := &models.YearInReview{
+		UserID:   user.ID,
+		UserName: user.Name,
+		Year:     year,
+		TimeZone: loc.String(),
+		TopMenus: []models.FavoriteMenu{},
+	}
+
+	seen := make(map[int]bool)
+	var yearOrders []userOrder
+	menuCounts := make(map[int]*models.FavoriteMenu)
+	categoryCounts := make(map[string]int)
+	for _, order := range orders {
+		if order.OrderDate.Before(start) {
+			seen[order.MenuID] = true
+			continue
+		}
+		if !order.OrderDate.Before(end) {
+			break
+		}
+
+		yearOrders = append(yearOrders, order)
+		review.TotalSpend += order.amount()
+		if !seen[order.MenuID] {
+			review.MenusConquered++
+			seen[order.MenuID] = true
+		}
+		if menuCounts[order.MenuID] == nil {
+			menuCounts[order.MenuID] = &models.FavoriteMenu{MenuID: order.MenuID, Name: order.MenuName}
+		}
+		menuCounts[order.MenuID].Count += order.Quantity
+		categoryCounts[order.Category] += order.Quantity
+	}
+
+	review.MenusEaten = len(menuCounts)
+	for _, menu := range menuCounts {
+		review.TopMenus = append(review.TopMenus, *menu)
+	}
+	sort.Slice(review.TopMenus, func(i, j int) bool {
+		if review.TopMenus[i].Count != review.TopMenus[j].Count {
+			return review.TopMenus[i].Count > review.TopMenus[j].Count
+		}
+		return review.TopMenus[i].MenuID < review.TopMenus[j].MenuID
+	})
+	if len(review.TopMenus) > yearInReviewTopMenuCount {
+		review.TopMenus = review.TopMenus[:yearInReviewTopMenuCount]
+	}
+	if len(categoryCounts) > 0 {
+		category := mostFrequent(categoryCounts)
+		review.TopCategory = &models.FavoriteCategory{Category: category, Count: categoryCounts[category]}
+	}
+
+	visits := groupVisits(yearOrders)
+	review.TotalVisits = len(visits)
+	timeOfDayCounts := make(map[string]int)
+	visitedDays := make(map[time.Time]bool)
+	for _, v := range visits {
+		local := v.At.In(loc)
+		timeOfDayCounts[timeOfDay(local)]++
+		visitedDays[period.Day(local, loc).From] = true
+	}
+	review.MostCommonTimeOfDay = mostFrequent(timeOfDayCounts)
+	_, review.LongestStreak = streaks(visitedDays, end, func(t time.Time, n int) time.Time {
+		return t.AddDate(0, 0, n)
+	})
+
+	review.Percentile, review.ActiveUsers, err = getVisitPercentile(user.ID, viewerID, review.TotalVisits, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// getVisitPercentile 期間中に来店した他のユーザーのうち来店回数がvisitsより少ない人の割合と、
+// 期間中に来店したユーザーの数を取得
+// visitsと同じ条件で比べるため、他のユーザーの来店回数もviewerIDのユーザーから見える食事記録だけで数える
+// 来店回数は同じ日時に記録された注文を1回として数える
+func getVisitPercentile(userID, viewerID, visits int, start, end time.Time) (float64, int, error) {
+	visibility, visibilityArgs := visibilityCondition("o", viewerID)
+	query := `
+		SELECT 
+			COUNT(*) as active_users,
+			COALESCE(SUM(user_id <> ?), 0) as others,
+			COALESCE(SUM(user_id <> ? AND visits < ?), 0) as fewer
+		FROM (
+			SELECT o.user_id, COUNT(DISTINCT o.order_date) as visits
+			FROM orders o
+			WHERE o.order_date >= ? AND o.order_date < ? AND ` + visibility + `
+			GROUP BY o.user_id
+		) v`
+
+	args := append([]interface{}{userID, userID, visits, start, end}, visibilityArgs...)
+	var activeUsers, others, fewer int
+	err := DB.QueryRow(query, args...).Scan(&activeUsers, &others, &fewer)
+	if err != nil {
+		return 0, 0, err
+	}
+	if visits == 0 || others == 0 {
+		return 0, activeUsers, nil
+	}
+
+	percentile := float64(fewer) / float64(others) * 100
+	return math.Round(percentile*10) / 10, activeUsers, nil
+}
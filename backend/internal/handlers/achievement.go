@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+)
+
+// GetUserAchievements ユーザーの実績の獲得状況と進捗を取得（本人のみ）
+// 進捗は非公開の食事記録からも計算するため、本人以外には返さない
+func GetUserAchievements(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	achievements, err := database.GetUserAchievements(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get achievements", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(achievements)
+}
+
+// evaluateAchievements 食事記録や評価の保存後に実績の獲得条件を判定し、獲得した実績を通知
+// 判定に失敗しても保存自体は成功しているため、ログに記録するだけにする
+func evaluateAchievements(userID int) {
+	unlocked, err := database.EvaluateAchievements(userID)
+	if err != nil {
+		log.Printf("Failed to evaluate achievements for user %d: %v", userID, err)
+		return
+	}
+	for _, achievement := range unlocked {
+		log.Printf("User %d unlocked achievement %s", userID, achievement.Code)
+		notify(models.Notification{
+			UserID:    userID,
+			Type:      models.NotificationAchievement,
+			Message:   fmt.Sprintf("実績「%s」を獲得しました", achievement.Name),
+			DedupeKey: "achievement:" + achievement.Code,
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gachimatsu-backend/internal/auth"
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/middleware"
+	"gachimatsu-backend/internal/models"
+)
+
+// トークンの有効期限
+const (
+	verifyEmailTokenTTL   = 24 * time.Hour
+	passwordResetTokenTTL = 30 * time.Minute
+)
+
+// 確認メール・再設定メールのリクエストの回数制限
+const (
+	authRequestsPerEmail      = 3
+	authRequestsPerEmailEvery = 15 * time.Minute
+	authRequestsPerIP         = 20
+	authRequestsPerIPEvery    = time.Hour
+)
+
+// loginAction ログイン試行の回数制限に使うアクション名
+const loginAction = "login"
+
+// ログイン試行の回数制限（入力ミスを考慮してメールの送信より緩くする）
+const (
+	loginAttemptsPerEmail      = 10
+	loginAttemptsPerEmailEvery = 15 * time.Minute
+	loginAttemptsPerIP         = 100
+	loginAttemptsPerIPEvery    = time.Hour
+)
+
+// sessionTTL セッションの有効期間を環境変数SESSION_TTL_HOURSから取得（デフォルト: 30日）
+func sessionTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("SESSION_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = 30 * 24
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// appURL フロントエンドのURLを環境変数APP_BASE_URLから組み立てる（デフォルト: http://localhost:3000）
+func appURL(path string, query url.Values) string {
+	base := os.Getenv("APP_BASE_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	u := strings.TrimRight(base, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// apiURL このAPIのURLを環境変数API_BASE_URLから組み立てる（デフォルト: http://localhost:8080/api/v1）
+func apiURL(path string) string {
+	base := os.Getenv("API_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080/api/v1"
+	}
+	return strings.TrimRight(base, "/") + path
+}
+
+// mailLanguage リクエストのAccept-Languageからメールの言語を決める
+func mailLanguage(r *http.Request) string {
+	if lang := r.Header.Get("Accept-Language"); lang != "" {
+		return mailer.NormalizeLanguage(lang)
+	}
+	return mailer.DefaultLanguage()
+}
+
+// sendTemplatedEmail テンプレートからメールを作成して送信待ちのキューに追加
+func sendTemplatedEmail(name, lang, to string, data interface{}) error {
+	msg, err := mailer.Render(name, lang, to, data)
+	if err != nil {
+		return err
+	}
+	return database.EnqueueEmail(msg.To, msg.Subject, msg.Body)
+}
+
+// clientIP 回数制限に使うクライアントのIPアドレス
+// X-Forwarded-Forは偽装できるため使わない
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// allowAuthRequest メールアドレスとIPアドレスごとの回数制限を確認
+// 制限を超えている場合はエラーレスポンスを書き込んでfalseを返す
+func allowAuthRequest(w http.ResponseWriter, r *http.Request, action, email string) bool {
+	type rateLimit struct {
+		key    string
+		limit  int
+		window time.Duration
+	}
+	limits := []rateLimit{
+		{"ip:" + clientIP(r), authRequestsPerIP, authRequestsPerIPEvery},
+		{"email:" + email, authRequestsPerEmail, authRequestsPerEmailEvery},
+	}
+	if action == loginAction {
+		limits = []rateLimit{
+			{"ip:" + clientIP(r), loginAttemptsPerIP, loginAttemptsPerIPEvery},
+			{"email:" + email, loginAttemptsPerEmail, loginAttemptsPerEmailEvery},
+		}
+	}
+	for _, limit := range limits {
+		allowed, err := database.AllowAuthRequest(action, limit.key, limit.limit, limit.window)
+		if err != nil {
+			http.Error(w, "Failed to check rate limit", http.StatusInternalServerError)
+			return false
+		}
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(limit.window.Seconds())))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return false
+		}
+	}
+	return true
+}
+
+// decodeEmailRequest リクエストからメールアドレスを取得
+func decodeEmailRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req models.EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return "", false
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		http.Error(w, "Email is required", http.StatusBadRequest)
+		return "", false
+	}
+	return email, true
+}
+
+// Login メールアドレスとパスワードでログインし、セッショントークンを発行
+// パスワードの総当たりを防ぐため、メールアドレスとIPアドレスごとに試行回数を制限する
+func Login(w http.ResponseWriter, r *http.Request) {
+	var req models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !allowAuthRequest(w, r, loginAction, email) {
+		return
+	}
+
+	user, err := database.GetUserByEmail(email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// アカウントの有無を応答時間から推測されないよう、存在する場合と同じ計算を行う
+			auth.CheckDummyPassword(req.Password)
+			http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		}
+		return
+	}
+	hash, err := database.GetPasswordHash(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+	// パスワード未設定のユーザーはパスワード再設定でパスワードを設定する
+	if hash == "" {
+		auth.CheckDummyPassword(req.Password)
+		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+		return
+	}
+	if ok, err := auth.CheckPassword(hash, req.Password); err != nil || !ok {
+		http.Error(w, "Invalid email or password", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := auth.NewSessionToken()
+	if err != nil {
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+	expiresAt := time.Now().Add(sessionTTL())
+	if err := database.CreateSession(user.ID, auth.HashSessionToken(token), expiresAt); err != nil {
+		http.Error(w, "Failed to log in", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.LoginResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		User:      user,
+	})
+}
+
+// Logout 現在のセッションを無効化
+func Logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := middleware.BearerToken(r)
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	if err := database.RevokeSession(auth.HashSessionToken(token)); err != nil {
+		http.Error(w, "Failed to log out", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequestEmailVerification メールアドレスの確認メールを送信
+// 登録の有無を推測されないよう、ユーザーが存在しない場合も同じレスポンスを返す
+func RequestEmailVerification(w http.ResponseWriter, r *http.Request) {
+	email, ok := decodeEmailRequest(w, r)
+	if !ok {
+		return
+	}
+	if !allowAuthRequest(w, r, auth.PurposeVerifyEmail, email) {
+		return
+	}
+
+	user, err := database.GetUserByEmail(email)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		http.Error(w, "Failed to request email verification", http.StatusInternalServerError)
+		return
+	case user.EmailVerifiedAt == nil:
+		if err := sendUserToken(r, user, auth.PurposeVerifyEmail, verifyEmailTokenTTL, "/verify-email", "verify_email"); err != nil {
+			log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+			http.Error(w, "Failed to request email verification", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmEmailVerification 確認メールのトークンでメールアドレスを確認済みにする
+func ConfirmEmailVerification(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := auth.VerifyToken(req.Token, auth.PurposeVerifyEmail, time.Now())
+	if err != nil {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err := database.VerifyUserEmail(claims.UserID, claims.TokenID, claims.Purpose); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to verify email", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	user, err := database.GetUserByID(claims.UserID)
+	if err != nil {
+		http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// RequestPasswordReset パスワード再設定メールを送信
+// 登録の有無を推測されないよう、ユーザーが存在しない場合も同じレスポンスを返す
+func RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	email, ok := decodeEmailRequest(w, r)
+	if !ok {
+		return
+	}
+	if !allowAuthRequest(w, r, auth.PurposePasswordReset, email) {
+		return
+	}
+
+	user, err := database.GetUserByEmail(email)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		http.Error(w, "Failed to request password reset", http.StatusInternalServerError)
+		return
+	default:
+		if err := sendUserToken(r, user, auth.PurposePasswordReset, passwordResetTokenTTL, "/reset-password", "password_reset"); err != nil {
+			log.Printf("Failed to send password reset email to user %d: %v", user.ID, err)
+			http.Error(w, "Failed to request password reset", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ConfirmPasswordReset 再設定メールのトークンでパスワードを再設定
+// 再設定すると既存のセッションはすべて無効になる
+func ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := auth.VerifyToken(req.Token, auth.PurposePasswordReset, time.Now())
+	if err != nil {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		if err == auth.ErrWeakPassword {
+			http.Error(w, "Password must be at least "+strconv.Itoa(auth.MinPasswordLength)+" characters", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := database.ResetPassword(claims.UserID, claims.TokenID, claims.Purpose, hash); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to reset password", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sendUserToken トークンを発行し、トークン付きのリンクをメールで送信
+func sendUserToken(r *http.Request, user *models.User, purpose string, ttl time.Duration, path, template string) error {
+	expiresAt := time.Now().Add(ttl)
+	tokenID, err := database.CreateUserToken(user.ID, purpose, expiresAt)
+	if err != nil {
+		return err
+	}
+	token := auth.SignToken(auth.TokenClaims{
+		Purpose:   purpose,
+		UserID:    user.ID,
+		TokenID:   tokenID,
+		ExpiresAt: expiresAt,
+	})
+
+	return sendTemplatedEmail(template, mailLanguage(r), user.Email, map[string]interface{}{
+		"Name":             user.Name,
+		"URL":              appURL(path, url.Values{"token": {token}}),
+		"ExpiresInMinutes": int(ttl.Minutes()),
+	})
+}
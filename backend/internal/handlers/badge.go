@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"gachimatsu-backend/internal/cards"
+	"gachimatsu-backend/internal/database"
+)
+
+// badgeMaxAge バッジをキャッシュしてよい秒数
+const badgeMaxAge = 300
+
+// defaultBadgeLabel バッジの左側に表示する文字列のデフォルト値
+const defaultBadgeLabel = "matsuya"
+
+// GetProgressBadge ユーザーの制覇状況をSVGバッジで取得するハンドラー（ログイン不要）
+// 設定でバッジを公開しているユーザーのみ表示でき、公開（public）の食事記録だけを数える
+// category（カテゴリ名）を指定するとカテゴリごとの制覇状況を表示し、左側の文字列もカテゴリ名になる
+func GetProgressBadge(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := database.GetUserSettings(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get user settings", http.StatusInternalServerError)
+		return
+	}
+	if !settings.PublicBadge {
+		http.Error(w, "Badge not found", http.StatusNotFound)
+		return
+	}
+
+	progress, err := database.GetUserProgress(user, 0)
+	if err != nil {
+		http.Error(w, "Failed to get progress", http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	label := defaultBadgeLabel
+	percentage, conquered, total := progress.Percentage, progress.Conquered, progress.Total
+	if category := query.Get("category"); category != "" {
+		found := false
+		for _, c := range progress.Categories {
+			if c.Category == category {
+				found = true
+				conquered, total = c.Conquered, c.Total
+				percentage = database.ProgressPercentage(c.Conquered, c.Total)
+				break
+			}
+		}
+		if !found {
+			http.Error(w, "Category not found", http.StatusNotFound)
+			return
+		}
+		label = category
+	}
+
+	badge := cards.ProgressBadgeSVG(label, percentage, conquered, total)
+	sum := sha256.Sum256(badge)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(badgeMaxAge))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(badge)
+}
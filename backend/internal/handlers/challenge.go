@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/middleware"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// GetChallenges チャレンジの一覧を取得
+func GetChallenges(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", "active", "upcoming", "ended":
+	default:
+		http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+		return
+	}
+
+	challenges, err := database.GetChallenges(status)
+	if err != nil {
+		http.Error(w, "Failed to get challenges", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(challenges)
+}
+
+// GetChallenge 特定のチャレンジを取得
+func GetChallenge(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+
+	challenge, err := database.GetChallengeByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Challenge not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get challenge", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(challenge)
+}
+
+// CreateChallenge チャレンジを作成
+func CreateChallenge(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Title == "" {
+		http.Error(w, "Title is required", http.StatusBadRequest)
+		return
+	}
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() || !req.StartsAt.Before(req.EndsAt) {
+		http.Error(w, "starts_at must be before ends_at", http.StatusBadRequest)
+		return
+	}
+	if len(req.MenuIDs) == 0 && len(req.Categories) == 0 {
+		http.Error(w, "At least one target menu or category is required", http.StatusBadRequest)
+		return
+	}
+	switch req.Rule {
+	case "":
+		req.Rule = models.ChallengeRuleAll
+		req.RequiredCount = nil
+	case models.ChallengeRuleAll:
+		req.RequiredCount = nil
+	case models.ChallengeRuleCount:
+		if req.RequiredCount == nil || *req.RequiredCount <= 0 {
+			http.Error(w, "required_count must be positive for count rule", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Invalid rule", http.StatusBadRequest)
+		return
+	}
+
+	challenge, err := database.CreateChallenge(req)
+	if err != nil {
+		http.Error(w, "Failed to create challenge", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(challenge)
+}
+
+// JoinChallenge 呼び出し元のユーザーをチャレンジに参加させる
+func JoinChallenge(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+
+	if err := database.JoinChallenge(id, caller.ID); err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			http.Error(w, "Challenge not found", http.StatusNotFound)
+		case database.ErrChallengeEnded:
+			http.Error(w, "Challenge has ended", http.StatusConflict)
+		case database.ErrAlreadyJoined:
+			http.Error(w, "User has already joined the challenge", http.StatusConflict)
+		default:
+			http.Error(w, "Failed to join challenge", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	progress, err := database.GetChallengeProgress(id, caller.ID, caller.ID)
+	if err != nil {
+		http.Error(w, "Failed to get challenge progress", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(progress)
+}
+
+// GetChallengeProgress チャレンジ参加者の進捗を取得
+// 呼び出し元から見える食事記録だけを数える
+func GetChallengeProgress(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+	progress, err := database.GetChallengeProgress(id, userID, viewerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Participant not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get challenge progress", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
+// GetChallengeLeaderboard チャレンジのリーダーボードを取得
+// 呼び出し元から見える食事記録だけを数える
+func GetChallengeLeaderboard(w http.ResponseWriter, r *http.Request) {
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+	leaderboard, err := database.GetChallengeLeaderboard(id, parseLimit(r, 50), viewerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Challenge not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get challenge leaderboard", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// challengeID URLパラメータのチャレンジIDを取得
+// IDが不正な場合はエラーレスポンスを書き込んでfalseを返す
+func challengeID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid challenge ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
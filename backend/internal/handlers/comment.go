@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// maxCommentLength コメント本文の最大文字数
+const maxCommentLength = 1000
+
+// maxEmojiLength リアクションの絵文字の最大バイト数
+const maxEmojiLength = 32
+
+// requireVisibleOrder URLパラメータの食事記録IDを取得し、呼び出し元から見える記録か確認
+// 記録が存在しないか見えない場合はエラーレスポンスを書き込んでfalseを返す
+func requireVisibleOrder(w http.ResponseWriter, r *http.Request, caller *models.User) (orderID, ownerID int, ok bool) {
+	orderID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	ownerID, err = database.GetVisibleOrderOwner(orderID, caller.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Order not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get order", http.StatusInternalServerError)
+		}
+		return 0, 0, false
+	}
+
+	return orderID, ownerID, true
+}
+
+// decodeCommentBody リクエストからコメント本文を取得
+// 本文が不正な場合はエラーレスポンスを書き込んでfalseを返す
+func decodeCommentBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req models.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return "", false
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		http.Error(w, "Comment body is required", http.StatusBadRequest)
+		return "", false
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		http.Error(w, fmt.Sprintf("Comment must be at most %d characters", maxCommentLength), http.StatusBadRequest)
+		return "", false
+	}
+	return body, true
+}
+
+// requireComment URLパラメータのコメントを取得
+// コメントが存在しない場合はエラーレスポンスを書き込んでfalseを返す
+func requireComment(w http.ResponseWriter, r *http.Request, orderID int) (*models.OrderComment, bool) {
+	commentID, err := strconv.Atoi(mux.Vars(r)["comment_id"])
+	if err != nil {
+		http.Error(w, "Invalid comment ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	comment, err := database.GetOrderComment(orderID, commentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Comment not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get comment", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	return comment, true
+}
+
+// GetOrderComments 食事記録のコメント一覧を取得
+func GetOrderComments(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, _, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+
+	comments, err := database.GetOrderComments(orderID)
+	if err != nil {
+		http.Error(w, "Failed to get comments", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+// CreateOrderComment 食事記録にコメントを投稿し、記録者に通知
+func CreateOrderComment(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, ownerID, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+	body, ok := decodeCommentBody(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := database.CreateOrderComment(orderID, caller.ID, body)
+	if err != nil {
+		http.Error(w, "Failed to create comment", http.StatusInternalServerError)
+		return
+	}
+
+	if ownerID != caller.ID {
+		notify(models.Notification{
+			UserID:  ownerID,
+			Type:    models.NotificationComment,
+			ActorID: &caller.ID,
+			OrderID: &orderID,
+			Message: fmt.Sprintf("%sさんがあなたの食事記録にコメントしました", caller.Name),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// UpdateOrderComment コメントを編集（投稿者のみ）
+func UpdateOrderComment(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, _, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+	comment, ok := requireComment(w, r, orderID)
+	if !ok {
+		return
+	}
+	if comment.UserID != caller.ID {
+		http.Error(w, "Only the author can edit the comment", http.StatusForbidden)
+		return
+	}
+	body, ok := decodeCommentBody(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := database.UpdateOrderComment(orderID, comment.ID, body)
+	if err != nil {
+		http.Error(w, "Failed to update comment", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comment)
+}
+
+// DeleteOrderComment コメントを削除（投稿者または記録者のみ）
+func DeleteOrderComment(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, ownerID, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+	comment, ok := requireComment(w, r, orderID)
+	if !ok {
+		return
+	}
+	if comment.UserID != caller.ID && ownerID != caller.ID {
+		http.Error(w, "Only the author or the record owner can delete the comment", http.StatusForbidden)
+		return
+	}
+
+	if err := database.DeleteOrderComment(orderID, comment.ID); err != nil {
+		http.Error(w, "Failed to delete comment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireEmoji URLパラメータのリアクションの絵文字を取得
+// 絵文字が不正な場合はエラーレスポンスを書き込んでfalseを返す
+func requireEmoji(w http.ResponseWriter, r *http.Request) (string, bool) {
+	emoji := mux.Vars(r)["emoji"]
+	valid := emoji != "" && len(emoji) <= maxEmojiLength && utf8.ValidString(emoji) &&
+		strings.IndexFunc(emoji, func(c rune) bool { return unicode.IsSpace(c) || unicode.IsControl(c) }) < 0
+	if !valid {
+		http.Error(w, "Invalid emoji", http.StatusBadRequest)
+		return "", false
+	}
+	return emoji, true
+}
+
+// GetOrderReactions 食事記録の絵文字ごとのリアクション数を取得
+func GetOrderReactions(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, _, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+
+	reactions, err := database.GetOrderReactions(orderID, caller.ID)
+	if err != nil {
+		http.Error(w, "Failed to get reactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// AddOrderReaction 食事記録に絵文字でリアクションし、記録者に通知
+func AddOrderReaction(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, ownerID, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+	emoji, ok := requireEmoji(w, r)
+	if !ok {
+		return
+	}
+
+	added, err := database.AddOrderReaction(orderID, caller.ID, emoji)
+	if err != nil {
+		http.Error(w, "Failed to add reaction", http.StatusInternalServerError)
+		return
+	}
+
+	if added && ownerID != caller.ID {
+		notify(models.Notification{
+			UserID:  ownerID,
+			Type:    models.NotificationReaction,
+			ActorID: &caller.ID,
+			OrderID: &orderID,
+			Message: fmt.Sprintf("%sさんがあなたの食事記録に%sでリアクションしました", caller.Name, emoji),
+			// リアクションを取り消して付け直した場合は通知しない
+			DedupeKey: fmt.Sprintf("reaction:%d:%d:%s", orderID, caller.ID, emoji),
+		})
+	}
+
+	reactions, err := database.GetOrderReactions(orderID, caller.ID)
+	if err != nil {
+		http.Error(w, "Failed to get reactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// RemoveOrderReaction 食事記録へのリアクションを取り消す
+func RemoveOrderReaction(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+	orderID, _, ok := requireVisibleOrder(w, r, caller)
+	if !ok {
+		return
+	}
+	emoji, ok := requireEmoji(w, r)
+	if !ok {
+		return
+	}
+
+	if err := database.RemoveOrderReaction(orderID, caller.ID, emoji); err != nil {
+		http.Error(w, "Failed to remove reaction", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
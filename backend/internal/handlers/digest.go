@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/period"
+)
+
+// PreviewWeeklyDigest ユーザーの週間サマリーをHTMLで表示（本人のみ）
+// date（YYYY-MM-DD）を含む週のまとめを表示する（デフォルト: 前の週）
+// 言語はlang、なければAccept-Languageで決める
+func PreviewWeeklyDigest(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+
+	week := database.PreviousWeek(time.Now(), loc)
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		date, err := period.ParseDate(dateStr, loc)
+		if err != nil {
+			http.Error(w, "Invalid date parameter", http.StatusBadRequest)
+			return
+		}
+		week = period.Week(date, loc)
+	}
+
+	digest, err := database.GetWeeklyDigest(user.ID, week)
+	if err != nil {
+		http.Error(w, "Failed to get weekly digest", http.StatusInternalServerError)
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = mailLanguage(r)
+	}
+	html, err := mailer.RenderHTML("weekly_digest", lang, digest)
+	if err != nil {
+		http.Error(w, "Failed to render weekly digest", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(html))
+}
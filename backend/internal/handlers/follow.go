@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// FollowUser ユーザーをフォロー（本人のみ）
+func FollowUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FollowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.UserID == user.ID {
+		http.Error(w, "Users cannot follow themselves", http.StatusBadRequest)
+		return
+	}
+	if _, err := database.GetUserByID(req.UserID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "User to follow not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	followed, err := database.FollowUser(user.ID, req.UserID)
+	if err != nil {
+		http.Error(w, "Failed to follow user", http.StatusInternalServerError)
+		return
+	}
+
+	// フォローを解除して再度フォローした場合は通知しない
+	if followed {
+		notify(models.Notification{
+			UserID:    req.UserID,
+			Type:      models.NotificationFollow,
+			ActorID:   &user.ID,
+			Message:   fmt.Sprintf("%sさんがあなたをフォローしました", user.Name),
+			DedupeKey: fmt.Sprintf("follow:%d", user.ID),
+		})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnfollowUser ユーザーのフォローを解除（本人のみ）
+func UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+	followeeID, err := strconv.Atoi(mux.Vars(r)["followee_id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.UnfollowUser(user.ID, followeeID); err != nil {
+		http.Error(w, "Failed to unfollow user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFollowers ユーザーのフォロワー一覧を取得
+func GetFollowers(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	followers, err := database.GetFollowers(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get followers", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(followers)
+}
+
+// GetFollowing ユーザーがフォローしているユーザーの一覧を取得
+func GetFollowing(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	following, err := database.GetFollowing(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get following users", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(following)
+}
+
+// GetUserFeed フォローしているユーザーのアクティビティフィードを取得（本人のみ）
+// cursorに前のページのnext_cursorを指定すると続きを取得する
+func GetUserFeed(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	feed, err := database.GetFollowingFeed(user.ID, r.URL.Query().Get("cursor"), parseLimit(r, 20))
+	if err != nil {
+		if err == database.ErrInvalidFeedCursor {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to get feed", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(feed)
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// requireGroupMember URLパラメータのグループIDと呼び出し元を取得し、呼び出し元がメンバーか確認
+// グループが存在しないかメンバーでない場合はエラーレスポンスを書き込んでfalseを返す
+func requireGroupMember(w http.ResponseWriter, r *http.Request) (*models.Group, *models.User, string, bool) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return nil, nil, "", false
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid group ID", http.StatusBadRequest)
+		return nil, nil, "", false
+	}
+
+	group, err := database.GetGroupByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Group not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get group", http.StatusInternalServerError)
+		}
+		return nil, nil, "", false
+	}
+
+	role, err := database.GetGroupRole(group.ID, caller.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Not a member of the group", http.StatusForbidden)
+		} else {
+			http.Error(w, "Failed to get group membership", http.StatusInternalServerError)
+		}
+		return nil, nil, "", false
+	}
+
+	return group, caller, role, true
+}
+
+// GetMyGroups 呼び出し元が参加しているグループの一覧を取得
+func GetMyGroups(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+
+	groups, err := database.GetUserGroups(caller.ID)
+	if err != nil {
+		http.Error(w, "Failed to get groups", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(groups)
+}
+
+// CreateGroup グループを作成（呼び出し元がオーナーになる）
+func CreateGroup(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	group, err := database.CreateGroup(req.Name, caller.ID)
+	if err != nil {
+		http.Error(w, "Failed to create group", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+// JoinGroup 招待コードでグループに参加
+func JoinGroup(w http.ResponseWriter, r *http.Request) {
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.JoinGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	inviteCode := strings.ToUpper(strings.TrimSpace(req.InviteCode))
+	if inviteCode == "" {
+		http.Error(w, "Invite code is required", http.StatusBadRequest)
+		return
+	}
+
+	group, err := database.JoinGroupByInviteCode(inviteCode, caller.ID)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			http.Error(w, "Invalid invite code", http.StatusNotFound)
+		case database.ErrAlreadyGroupMember:
+			http.Error(w, "Already a member of the group", http.StatusConflict)
+		default:
+			http.Error(w, "Failed to join group", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(group)
+}
+
+// GetGroup グループの詳細を取得（メンバーのみ）
+func GetGroup(w http.ResponseWriter, r *http.Request) {
+	group, _, _, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// RegenerateGroupInviteCode グループの招待コードを作り直す（オーナーのみ）
+func RegenerateGroupInviteCode(w http.ResponseWriter, r *http.Request) {
+	group, _, role, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+	if role != models.GroupRoleOwner {
+		http.Error(w, "Only the owner can regenerate the invite code", http.StatusForbidden)
+		return
+	}
+
+	group, err := database.RegenerateInviteCode(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to regenerate invite code", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(group)
+}
+
+// GetGroupMembers グループのメンバー一覧を取得
+func GetGroupMembers(w http.ResponseWriter, r *http.Request) {
+	group, _, _, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := database.GetGroupMembers(group.ID)
+	if err != nil {
+		http.Error(w, "Failed to get group members", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// RemoveGroupMember グループからメンバーを外す
+// メンバーは自分が抜けることができ、オーナーは他のメンバーを外せる（オーナー自身は抜けられない）
+func RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	group, caller, role, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	if userID != caller.ID && role != models.GroupRoleOwner {
+		http.Error(w, "Only the owner can remove other members", http.StatusForbidden)
+		return
+	}
+	targetRole, err := database.GetGroupRole(group.ID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Member not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get group membership", http.StatusInternalServerError)
+		}
+		return
+	}
+	if targetRole == models.GroupRoleOwner {
+		http.Error(w, "The owner cannot leave the group", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.RemoveGroupMember(group.ID, userID); err != nil {
+		http.Error(w, "Failed to remove group member", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetGroupProgress グループ全体のメニュー制覇状況を取得
+func GetGroupProgress(w http.ResponseWriter, r *http.Request) {
+	group, caller, _, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+
+	progress, err := database.GetGroupProgress(group.ID, caller.ID)
+	if err != nil {
+		http.Error(w, "Failed to get group progress", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
+
+// GetGroupMenuRanking グループのメンバーの注文と評価だけで集計したメニューランキングを取得
+func GetGroupMenuRanking(w http.ResponseWriter, r *http.Request) {
+	group, caller, _, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := parseRankingQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid ranking parameters: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.GroupID = group.ID
+	query.ViewerID = caller.ID
+
+	ranking, err := database.GetMenuRanking(query)
+	if err != nil {
+		http.Error(w, "Failed to get group menu ranking", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ranking)
+}
+
+// GetGroupLeaderboard グループのメンバーだけで順位を付けたユーザーリーダーボードを取得
+func GetGroupLeaderboard(w http.ResponseWriter, r *http.Request) {
+	group, caller, _, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+
+	kind := mux.Vars(r)["type"]
+	if !database.IsValidLeaderboardType(kind) {
+		http.Error(w, "Invalid leaderboard type", http.StatusBadRequest)
+		return
+	}
+	rng, err := parseRange(r)
+	if err != nil {
+		http.Error(w, "Invalid leaderboard parameters: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	leaderboard, err := database.GetLeaderboard(database.LeaderboardQuery{
+		Type:     kind,
+		Period:   rng,
+		Limit:    parseLimit(r, 10),
+		GroupID:  group.ID,
+		CallerID: caller.ID,
+	})
+	if err != nil {
+		http.Error(w, "Failed to get group leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaderboard)
+}
+
+// GetGroupFeed グループのメンバーのアクティビティフィードを取得
+func GetGroupFeed(w http.ResponseWriter, r *http.Request) {
+	group, caller, _, ok := requireGroupMember(w, r)
+	if !ok {
+		return
+	}
+
+	feed, err := database.GetGroupFeed(group.ID, caller.ID, r.URL.Query().Get("cursor"), parseLimit(r, 20))
+	if err != nil {
+		if err == database.ErrInvalidFeedCursor {
+			http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		} else {
+			http.Error(w, "Failed to get group feed", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(feed)
+}
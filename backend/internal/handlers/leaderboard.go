@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// GetLeaderboard ユーザーリーダーボードを取得
+// 呼び出し元から見える記録だけで順位を付け、呼び出し元が指定されている場合は、その順位もmeとして返す
+func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	kind := mux.Vars(r)["type"]
+	if !database.IsValidLeaderboardType(kind) {
+		http.Error(w, "Invalid leaderboard type", http.StatusBadRequest)
+		return
+	}
+
+	rng, err := parseRange(r)
+	if err != nil {
+		http.Error(w, "Invalid leaderboard parameters: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := database.LeaderboardQuery{
+		Type:   kind,
+		Period: rng,
+		Limit:  parseLimit(r, 10),
+	}
+	if callerID, ok := middleware.CurrentUserID(r.Context()); ok {
+		query.CallerID = callerID
+	}
+
+	leaderboard, err := database.GetLeaderboard(query)
+	if err != nil {
+		http.Error(w, "Failed to get leaderboard", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaderboard)
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"strconv"
 
+	"gachimatsu-backend/internal/database"
 	"gachimatsu-backend/internal/models"
 
 	"github.com/gorilla/mux"
@@ -71,4 +73,120 @@ func GetMenu(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(menu)
-} 
\ No newline at end of file
+}
+
+// GetSimilarMenus 類似メニューを取得
+// co_order_weight/category_weight/price_weight/rating_weightで類似度の重みを指定できる
+func GetSimilarMenus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid menu ID", http.StatusBadRequest)
+		return
+	}
+
+	weights := database.DefaultSimilarityWeights()
+	params := []struct {
+		name  string
+		value *float64
+	}{
+		{"co_order_weight", &weights.CoOrder},
+		{"category_weight", &weights.Category},
+		{"price_weight", &weights.Price},
+		{"rating_weight", &weights.Rating},
+	}
+	for _, param := range params {
+		if valueStr := r.URL.Query().Get(param.name); valueStr != "" {
+			value, err := strconv.ParseFloat(valueStr, 64)
+			if err != nil || value < 0 {
+				http.Error(w, "Invalid "+param.name+" parameter", http.StatusBadRequest)
+				return
+			}
+			*param.value = value
+		}
+	}
+
+	if _, err := database.GetMenuByID(id); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Menu not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get menu", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	similarMenus, err := database.GetSimilarMenus(id, weights, parseLimit(r, 5))
+	if err != nil {
+		http.Error(w, "Failed to get similar menus", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(similarMenus)
+}
+
+// UpdateMenu メニューを更新（管理API）
+// 価格を変更した場合は価格履歴に記録される
+func UpdateMenu(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid menu ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateMenuRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Price != nil && *req.Price < 0 {
+		http.Error(w, "Price must not be negative", http.StatusBadRequest)
+		return
+	}
+	if (req.Name != nil && *req.Name == "") || (req.Category != nil && *req.Category == "") {
+		http.Error(w, "Name and category must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	menu, err := database.UpdateMenu(id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Menu not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to update menu", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(menu)
+}
+
+// GetMenuPriceHistory メニューの価格履歴を取得
+func GetMenuPriceHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid menu ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := database.GetMenuByID(id); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Menu not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get menu", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	history, err := database.GetMenuPriceHistory(id)
+	if err != nil {
+		http.Error(w, "Failed to get menu price history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
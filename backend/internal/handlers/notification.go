@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// notify ユーザーへの通知を作成
+// 通知の作成に失敗しても元の操作は成功しているため、ログに記録するだけにする
+func notify(notification models.Notification) {
+	if _, err := database.CreateNotification(&notification); err != nil {
+		log.Printf("Failed to create %s notification for user %d: %v", notification.Type, notification.UserID, err)
+	}
+}
+
+// notifyBudgetWarning 今月の支出が予算を超えた、または超える見込みになった場合に通知
+// 同じ月の同じ警告は1回だけ通知する
+func notifyBudgetWarning(user *models.User) {
+	loc := userTimeZone(user)
+	status, err := database.GetBudgetStatus(user.ID, time.Now().In(loc), loc)
+	if err != nil {
+		log.Printf("Failed to get budget status for user %d: %v", user.ID, err)
+		return
+	}
+	if status.MonthlyBudget == nil {
+		return
+	}
+
+	switch {
+	case status.Spent > *status.MonthlyBudget:
+		notify(models.Notification{
+			UserID:    user.ID,
+			Type:      models.NotificationBudgetWarning,
+			Message:   fmt.Sprintf("%sの支出が月間予算（%d円）を超えました", status.Month, *status.MonthlyBudget),
+			DedupeKey: "budget_exceeded:" + status.Month,
+		})
+	case status.Warning:
+		notify(models.Notification{
+			UserID:    user.ID,
+			Type:      models.NotificationBudgetWarning,
+			Message:   fmt.Sprintf("%sの支出が月間予算（%d円）を超える見込みです", status.Month, *status.MonthlyBudget),
+			DedupeKey: "budget_warning:" + status.Month,
+		})
+	}
+}
+
+// GetUserNotifications ユーザーの通知を新しい順に取得（本人のみ）
+// unread=trueで未読の通知だけを、before（通知ID）でそれより古い通知を取得する
+func GetUserNotifications(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	unreadOnly := query.Get("unread") == "true"
+	beforeID := 0
+	if beforeStr := query.Get("before"); beforeStr != "" {
+		var err error
+		beforeID, err = strconv.Atoi(beforeStr)
+		if err != nil || beforeID <= 0 {
+			http.Error(w, "Invalid before parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	notifications, err := database.GetNotifications(user.ID, unreadOnly, beforeID, parseLimit(r, 20))
+	if err != nil {
+		http.Error(w, "Failed to get notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// GetUnreadNotificationCount ユーザーの未読の通知数を取得（本人のみ）
+func GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	count, err := database.GetUnreadNotificationCount(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get unread notification count", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.UnreadCount{UnreadCount: count})
+}
+
+// MarkNotificationRead 通知を既読にする（本人のみ）
+func MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+	notificationID, err := strconv.Atoi(mux.Vars(r)["notification_id"])
+	if err != nil {
+		http.Error(w, "Invalid notification ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.MarkNotificationRead(user.ID, notificationID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Notification not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to mark notification as read", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllNotificationsRead ユーザーの未読の通知をすべて既読にする（本人のみ）
+func MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := database.MarkAllNotificationsRead(user.ID); err != nil {
+		http.Error(w, "Failed to mark notifications as read", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.UnreadCount{UnreadCount: 0})
+}
+
+// GetNotificationPreferences ユーザーの通知の受信設定を取得（本人のみ）
+func GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	preferences, err := database.GetNotificationPreferences(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preferences)
+}
+
+// UpdateNotificationPreferences ユーザーの通知の受信設定を更新（本人のみ）
+func UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	for _, preference := range req.Preferences {
+		if !models.IsValidNotificationType(preference.Type) {
+			http.Error(w, "Invalid notification type: "+preference.Type, http.StatusBadRequest)
+			return
+		}
+	}
+
+	preferences, err := database.UpdateNotificationPreferences(user.ID, req.Preferences)
+	if err != nil {
+		http.Error(w, "Failed to update notification preferences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preferences)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/middleware"
+	"gachimatsu-backend/internal/models"
+)
+
+// GetUserOrders ユーザーの食事記録を新しい順に取得
+// 呼び出し元から見える公開範囲の記録だけを返す
+func GetUserOrders(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+	orders, err := database.GetOrdersByUserID(user.ID, viewerID, parseLimit(r, 50))
+	if err != nil {
+		http.Error(w, "Failed to get orders", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(orders)
+}
+
+// CreateUserOrder 食事記録を作成（本人のみ）
+func CreateUserOrder(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.MenuID <= 0 {
+		http.Error(w, "Menu ID is required", http.StatusBadRequest)
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+	if req.Quantity < 0 {
+		http.Error(w, "Quantity must be positive", http.StatusBadRequest)
+		return
+	}
+	if req.Visibility == "" {
+		req.Visibility = models.VisibilityPublic
+	}
+	if !models.IsValidVisibility(req.Visibility) {
+		http.Error(w, "Invalid visibility", http.StatusBadRequest)
+		return
+	}
+
+	order := models.Order{
+		UserID:     user.ID,
+		MenuID:     req.MenuID,
+		Quantity:   req.Quantity,
+		Visibility: req.Visibility,
+	}
+	if req.OrderDate != nil {
+		order.OrderDate = *req.OrderDate
+	}
+
+	if err := database.CreateOrder(&order); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Menu not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to create order", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	evaluateAchievements(user.ID)
+	notifyBudgetWarning(user)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gachimatsu-backend/internal/period"
+)
+
+// parseLimit クエリパラメータからlimitを取得（不正な値の場合はデフォルト値）
+func parseLimit(r *http.Request, defaultLimit int) int {
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			return parsedLimit
+		}
+	}
+	return defaultLimit
+}
+
+// parseLocation クエリパラメータtzからタイムゾーンを取得
+func parseLocation(r *http.Request) (*time.Location, error) {
+	return period.LoadLocation(r.URL.Query().Get("tz"))
+}
+
+// parseRange クエリパラメータから集計期間をtzのタイムゾーンで取得
+func parseRange(r *http.Request) (period.Range, error) {
+	loc, err := parseLocation(r)
+	if err != nil {
+		return period.Range{}, err
+	}
+	return parseRangeIn(r, loc)
+}
+
+// parseRangeIn クエリパラメータから集計期間をlocのタイムゾーンで取得
+// period（daily/weekly/monthly）とdate（基準日、デフォルト: 今日）で暦上の期間を、
+// from/to（YYYY-MM-DD、toは当日を含む）で任意の期間を指定する
+func parseRangeIn(r *http.Request, loc *time.Location) (period.Range, error) {
+	query := r.URL.Query()
+
+	var err error
+	kind := query.Get("period")
+	fromStr := query.Get("from")
+	toStr := query.Get("to")
+
+	if kind != "" {
+		if fromStr != "" || toStr != "" {
+			return period.Range{}, errors.New("period cannot be combined with from/to")
+		}
+		anchor := time.Now()
+		if dateStr := query.Get("date"); dateStr != "" {
+			anchor, err = period.ParseDate(dateStr, loc)
+			if err != nil {
+				return period.Range{}, errors.New("invalid date parameter")
+			}
+		}
+		return period.Of(kind, anchor, loc)
+	}
+
+	var rng period.Range
+	if fromStr != "" {
+		rng.From, err = period.ParseDate(fromStr, loc)
+		if err != nil {
+			return period.Range{}, errors.New("invalid from parameter")
+		}
+	}
+	if toStr != "" {
+		to, err := period.ParseDate(toStr, loc)
+		if err != nil {
+			return period.Range{}, errors.New("invalid to parameter")
+		}
+		rng.To = to.AddDate(0, 0, 1)
+	}
+	if !rng.From.IsZero() && !rng.To.IsZero() && !rng.From.Before(rng.To) {
+		return period.Range{}, errors.New("from must not be after to")
+	}
+
+	return rng, nil
+}
@@ -2,25 +2,94 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
+// parseRankingQuery クエリパラメータからランキングの取得条件を組み立てる
+// limit（デフォルト: 10）と集計期間（period/date/from/to/tz）、
+// スコア計算方法（scoring/order_weight/rating_weight/prior_mean/prior_weight/z）を受け付ける
+func parseRankingQuery(r *http.Request) (models.RankingQuery, error) {
+	rng, err := parseRange(r)
+	if err != nil {
+		return models.RankingQuery{}, err
+	}
+
+	scoring, err := parseScoringOptions(r)
+	if err != nil {
+		return models.RankingQuery{}, err
+	}
+
+	return models.RankingQuery{
+		Limit:   parseLimit(r, 10),
+		Period:  rng,
+		Scoring: scoring,
+	}, nil
+}
+
+// parseScoringOptions クエリパラメータからスコア計算方法を取得
+func parseScoringOptions(r *http.Request) (models.ScoringOptions, error) {
+	query := r.URL.Query()
+	opts := database.DefaultScoringOptions()
+
+	if method := query.Get("scoring"); method != "" {
+		opts.Method = method
+	}
+	if !database.IsValidScoringMethod(opts.Method) {
+		return opts, fmt.Errorf("unknown scoring method %q", opts.Method)
+	}
+
+	params := []struct {
+		name  string
+		value *float64
+	}{
+		{"order_weight", &opts.OrderWeight},
+		{"rating_weight", &opts.RatingWeight},
+		{"prior_weight", &opts.PriorWeight},
+		{"z", &opts.WilsonZ},
+	}
+	for _, param := range params {
+		if valueStr := query.Get(param.name); valueStr != "" {
+			value, err := strconv.ParseFloat(valueStr, 64)
+			if err != nil || value < 0 {
+				return opts, fmt.Errorf("invalid %s parameter", param.name)
+			}
+			*param.value = value
+		}
+	}
+
+	if priorMeanStr := query.Get("prior_mean"); priorMeanStr != "" {
+		priorMean, err := strconv.ParseFloat(priorMeanStr, 64)
+		if err != nil || priorMean < 1 || priorMean > 5 {
+			return opts, errors.New("invalid prior_mean parameter")
+		}
+		opts.PriorMean = &priorMean
+	}
+
+	if opts.Method == models.ScoringBayesian && opts.PriorWeight <= 0 {
+		return opts, errors.New("prior_weight must be positive")
+	}
+
+	return opts, nil
+}
+
 // GetPopularMenus 人気メニューランキングを取得するハンドラー
 func GetPopularMenus(w http.ResponseWriter, r *http.Request) {
-	// クエリパラメータからlimitを取得（デフォルト: 10）
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	query, err := parseRankingQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid ranking parameters: "+err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	popularMenus, err := database.GetPopularMenus(limit)
+	popularMenus, err := database.GetPopularMenus(query)
 	if err != nil {
 		http.Error(w, "Failed to get popular menus", http.StatusInternalServerError)
 		return
@@ -41,16 +110,13 @@ func GetPopularMenusByCategory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// クエリパラメータからlimitを取得（デフォルト: 10）
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
-		}
+	query, err := parseRankingQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid ranking parameters: "+err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	popularMenus, err := database.GetPopularMenusByCategory(category, limit)
+	popularMenus, err := database.GetPopularMenusByCategory(category, query)
 	if err != nil {
 		http.Error(w, "Failed to get popular menus by category", http.StatusInternalServerError)
 		return
@@ -60,18 +126,41 @@ func GetPopularMenusByCategory(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(popularMenus)
 }
 
-// GetMenuRanking 全体ランキングとカテゴリ別ランキングを取得するハンドラー
-func GetMenuRanking(w http.ResponseWriter, r *http.Request) {
+// GetTrendingMenus 時間減衰を適用したトレンドメニューランキングを取得するハンドラー
+func GetTrendingMenus(w http.ResponseWriter, r *http.Request) {
 	// クエリパラメータからlimitを取得（デフォルト: 10）
-	limitStr := r.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
-			limit = parsedLimit
+	limit := parseLimit(r, 10)
+
+	// クエリパラメータから半減期（日数）を取得（デフォルト: 環境変数の設定値）
+	halfLife := database.DefaultTrendingHalfLife()
+	if halfLifeStr := r.URL.Query().Get("half_life_days"); halfLifeStr != "" {
+		days, err := strconv.ParseFloat(halfLifeStr, 64)
+		if err != nil || days <= 0 {
+			http.Error(w, "Invalid half_life_days parameter", http.StatusBadRequest)
+			return
 		}
+		halfLife = time.Duration(days * float64(24*time.Hour))
+	}
+
+	trendingMenus, err := database.GetTrendingMenus(limit, halfLife)
+	if err != nil {
+		http.Error(w, "Failed to get trending menus", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trendingMenus)
+}
+
+// GetMenuRanking 全体ランキングとカテゴリ別ランキングを取得するハンドラー
+func GetMenuRanking(w http.ResponseWriter, r *http.Request) {
+	query, err := parseRankingQuery(r)
+	if err != nil {
+		http.Error(w, "Invalid ranking parameters: "+err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	ranking, err := database.GetMenuRanking(limit)
+	ranking, err := database.GetMenuRanking(query)
 	if err != nil {
 		http.Error(w, "Failed to get menu ranking", http.StatusInternalServerError)
 		return
@@ -80,3 +169,34 @@ func GetMenuRanking(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(ranking)
 }
+
+// GetMenuRankHistory メニューの順位の推移を取得するハンドラー
+// categoryを指定するとカテゴリ別ランキングでの順位を返す（デフォルト: 直近30日間の全体ランキング）
+func GetMenuRankHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid menu ID", http.StatusBadRequest)
+		return
+	}
+
+	rng, err := parseRange(r)
+	if err != nil {
+		http.Error(w, "Invalid ranking parameters: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if rng.IsZero() {
+		today := period.Day(time.Now(), period.DefaultLocation())
+		rng = period.Range{From: today.From.AddDate(0, 0, -29), To: today.To}
+	}
+
+	scope := database.RankingScope(r.URL.Query().Get("category"))
+	history, err := database.GetMenuRankHistory(id, scope, rng)
+	if err != nil {
+		http.Error(w, "Failed to get menu rank history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
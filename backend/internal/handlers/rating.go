@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+)
+
+// RateMenu ユーザーのメニュー評価を保存（本人のみ）
+// 同じメニューを評価済みの場合は評価を更新する
+func RateMenu(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.RateMenuRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.MenuID <= 0 {
+		http.Error(w, "Menu ID is required", http.StatusBadRequest)
+		return
+	}
+	if req.Rating < 1 || req.Rating > 5 {
+		http.Error(w, "Rating must be between 1 and 5", http.StatusBadRequest)
+		return
+	}
+	if req.Visibility == "" {
+		req.Visibility = models.VisibilityPublic
+	}
+	if !models.IsValidVisibility(req.Visibility) {
+		http.Error(w, "Invalid visibility", http.StatusBadRequest)
+		return
+	}
+
+	rating := models.MenuRating{
+		UserID:     user.ID,
+		MenuID:     req.MenuID,
+		Rating:     req.Rating,
+		Visibility: req.Visibility,
+	}
+	if err := database.RateMenu(&rating); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Menu not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to rate menu", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	evaluateAchievements(user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rating)
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gachimatsu-backend/internal/auth"
+	"gachimatsu-backend/internal/cards"
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// maxShareLinkDays 共有リンクの有効期間の最大日数
+const maxShareLinkDays = 365
+
+// sharedContentMaxAge 共有リンクの内容をキャッシュしてよい秒数（無効化が反映されるまでの最大時間）
+const sharedContentMaxAge = 300
+
+// requireShareLinkSecret 共有リンクのトークンを発行できるか確認
+// 署名の鍵が設定されていない場合は再起動でリンクが切れるため、503を返してfalseを返す
+func requireShareLinkSecret(w http.ResponseWriter) bool {
+	if !auth.HasConfiguredSecret() {
+		http.Error(w, "Share links are not available on this server", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// fillShareLinkURLs 共有リンクのトークンとURLを設定
+// トークンはリンクのIDを署名したもので、無効化はデータベースで確認する
+func fillShareLinkURLs(link *models.ShareLink) {
+	claims := auth.TokenClaims{
+		Purpose: auth.PurposeShareLink,
+		UserID:  link.UserID,
+		TokenID: link.ID,
+	}
+	if link.ExpiresAt != nil {
+		claims.ExpiresAt = *link.ExpiresAt
+	}
+	link.Token = auth.SignToken(claims)
+	link.URL = appURL("/share/"+link.Token, nil)
+	link.ImageURL = apiURL("/shared/" + link.Token + "/og.png")
+}
+
+// CreateShareLink 食事記録か制覇状況の共有リンクを作成（本人のみ）
+func CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok || !requireShareLinkSecret(w) {
+		return
+	}
+
+	var req models.CreateShareLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !models.IsValidShareTarget(req.Target) {
+		http.Error(w, "Target must be order or progress", http.StatusBadRequest)
+		return
+	}
+
+	link := models.ShareLink{UserID: user.ID, Target: req.Target}
+	switch req.Target {
+	case models.ShareTargetOrder:
+		if req.OrderID == nil {
+			http.Error(w, "Order ID is required", http.StatusBadRequest)
+			return
+		}
+		ownerID, err := database.GetOrderOwner(*req.OrderID)
+		if err != nil && err != sql.ErrNoRows {
+			http.Error(w, "Failed to get order", http.StatusInternalServerError)
+			return
+		}
+		if err == sql.ErrNoRows || ownerID != user.ID {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			return
+		}
+		link.OrderID = req.OrderID
+	case models.ShareTargetProgress:
+		if req.OrderID != nil {
+			http.Error(w, "Order ID is only allowed for order links", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays < 1 || *req.ExpiresInDays > maxShareLinkDays {
+			http.Error(w, "Expires in days must be between 1 and 365", http.StatusBadRequest)
+			return
+		}
+		expiresAt := time.Now().Add(time.Duration(*req.ExpiresInDays) * 24 * time.Hour)
+		link.ExpiresAt = &expiresAt
+	}
+
+	if err := database.CreateShareLink(&link); err != nil {
+		http.Error(w, "Failed to create share link", http.StatusInternalServerError)
+		return
+	}
+	fillShareLinkURLs(&link)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+}
+
+// GetShareLinks ユーザーの有効な共有リンクを取得（本人のみ）
+func GetShareLinks(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok || !requireShareLinkSecret(w) {
+		return
+	}
+
+	links, err := database.GetShareLinks(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get share links", http.StatusInternalServerError)
+		return
+	}
+	for i := range links {
+		fillShareLinkURLs(&links[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// RevokeShareLink 共有リンクを無効化（本人のみ）
+func RevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+	linkID, err := strconv.Atoi(mux.Vars(r)["link_id"])
+	if err != nil {
+		http.Error(w, "Invalid share link ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.RevokeShareLink(user.ID, linkID); err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Share link not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to revoke share link", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadSharedContent URLパラメータのトークンから共有リンクの内容を取得
+// 制覇状況は公開（public）の食事記録だけで計算する
+// リンクが不正・期限切れ・無効化済みの場合はエラーレスポンスを書き込んでfalseを返す
+func loadSharedContent(w http.ResponseWriter, r *http.Request) (*models.SharedContent, bool) {
+	token := mux.Vars(r)["token"]
+	claims, err := auth.VerifyToken(token, auth.PurposeShareLink, time.Now())
+	if err != nil {
+		http.Error(w, "Share link not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	link, err := database.GetShareLink(claims.TokenID)
+	if err == sql.ErrNoRows || (err == nil && link.UserID != claims.UserID) {
+		http.Error(w, "Share link not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "Failed to get share link", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	user, err := database.GetUserByID(link.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Share link not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	content := &models.SharedContent{
+		Target:   link.Target,
+		UserName: user.Name,
+		ImageURL: apiURL("/shared/" + token + "/og.png"),
+	}
+	if link.Target == models.ShareTargetOrder && link.OrderID != nil {
+		content.Order, err = database.GetSharedOrder(*link.OrderID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				http.Error(w, "Shared record has been deleted", http.StatusGone)
+			} else {
+				http.Error(w, "Failed to get order", http.StatusInternalServerError)
+			}
+			return nil, false
+		}
+	}
+
+	content.Progress, err = database.GetUserProgress(user, 0)
+	if err != nil {
+		http.Error(w, "Failed to get progress", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return content, true
+}
+
+// GetSharedContent 共有リンクの内容を取得（ログイン不要）
+func GetSharedContent(w http.ResponseWriter, r *http.Request) {
+	content, ok := loadSharedContent(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(sharedContentMaxAge))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(content)
+}
+
+// GetSharedImage 共有リンクのOGP画像をPNGで取得（ログイン不要）
+// 食事記録のリンクはメニュー名と写真、制覇状況のリンクはユーザー名と円グラフを載せる
+// 画像は載せる内容ごとにキャッシュされ、制覇状況が変わったときだけ描き直す
+func GetSharedImage(w http.ResponseWriter, r *http.Request) {
+	content, ok := loadSharedContent(w, r)
+	if !ok {
+		return
+	}
+
+	card := cards.ShareCard{
+		Title:      content.UserName,
+		Percentage: content.Progress.Percentage,
+		Conquered:  content.Progress.Conquered,
+		Total:      content.Progress.Total,
+	}
+	if content.Order != nil {
+		card.Title = content.Order.MenuName
+		card.ThumbnailURL = content.Order.MenuImageURL
+	}
+
+	image, err := cards.ShareImagePNG(card)
+	if err != nil {
+		http.Error(w, "Failed to render image", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(sharedContentMaxAge))
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(image)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// GetUserSpending ユーザーの支出の推移を取得（本人のみ）
+// interval（daily/weekly/monthly、デフォルト: daily）ごとに、集計期間（period/date/from/to）内の支出を返す
+// 期間の区切りはtz（デフォルト: ユーザーのタイムゾーン）で決める
+// 返す期間の数は最大1000件で、超える場合は400を返す
+func GetUserSpending(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = period.Daily
+	}
+	if interval != period.Daily && interval != period.Weekly && interval != period.Monthly {
+		http.Error(w, "Invalid interval parameter", http.StatusBadRequest)
+		return
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+	rng, err := parseRangeIn(r, loc)
+	if err != nil {
+		http.Error(w, "Invalid period parameters: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := database.GetSpendingSeries(user.ID, interval, rng, loc)
+	if err == database.ErrTooManySpendingBuckets {
+		http.Error(w, "Period is too long for the interval; narrow the period or use a longer interval", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to get spending", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+// GetUserBudget 月間予算に対する支出状況を取得（本人のみ）
+// month（YYYY-MM、デフォルト: 今月）で対象の月を指定する
+func GetUserBudget(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+
+	month := time.Now()
+	if monthStr := r.URL.Query().Get("month"); monthStr != "" {
+		month, err = time.ParseInLocation("2006-01", monthStr, loc)
+		if err != nil {
+			http.Error(w, "Invalid month parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	status, err := database.GetBudgetStatus(user.ID, month, loc)
+	if err != nil {
+		http.Error(w, "Failed to get budget status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// UpdateUserBudget 月間予算を設定（本人のみ）
+func UpdateUserBudget(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+
+	var req models.BudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.MonthlyBudget <= 0 {
+		http.Error(w, "Monthly budget must be positive", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.SetMonthlyBudget(user.ID, req.MonthlyBudget); err != nil {
+		http.Error(w, "Failed to update budget", http.StatusInternalServerError)
+		return
+	}
+
+	status, err := database.GetBudgetStatus(user.ID, time.Now(), loc)
+	if err != nil {
+		http.Error(w, "Failed to get budget status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -3,7 +3,11 @@ package handlers
 import (
 	"encoding/json"
 	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/middleware"
+	"gachimatsu-backend/internal/period"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // GetStats 統計情報を取得するハンドラー
@@ -17,3 +21,112 @@ func GetStats(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
 }
+
+// GetUserStats ユーザーごとの統計情報を取得するハンドラー
+// 呼び出し元から見える食事記録だけを数え、曜日・時間帯はtz（デフォルト: ユーザーのタイムゾーン）で集計する
+func GetUserStats(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+	stats, err := database.GetUserStats(user.ID, viewerID, loc)
+	if err != nil {
+		http.Error(w, "Failed to get user stats", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetPriceChangeReport 指定日以降のメニュー価格変更のレポートを取得するハンドラー
+// since（YYYY-MM-DD、必須）とtzで基準日を指定する
+func GetPriceChangeReport(w http.ResponseWriter, r *http.Request) {
+	loc, err := parseLocation(r)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+
+	sinceStr := r.URL.Query().Get("since")
+	if sinceStr == "" {
+		http.Error(w, "Since parameter is required", http.StatusBadRequest)
+		return
+	}
+	since, err := period.ParseDate(sinceStr, loc)
+	if err != nil {
+		http.Error(w, "Invalid since parameter", http.StatusBadRequest)
+		return
+	}
+
+	report, err := database.GetPriceChangeReport(since)
+	if err != nil {
+		http.Error(w, "Failed to get price change report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetUserCalendar ユーザーの1年間の来店カレンダーと連続記録を取得するハンドラー
+// year（デフォルト: 今年）の日ごとの来店回数を、tz（デフォルト: ユーザーのタイムゾーン）で集計する
+// 呼び出し元から見える食事記録だけを数える
+func GetUserCalendar(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return
+	}
+
+	year := time.Now().In(loc).Year()
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		year, err = strconv.Atoi(yearStr)
+		if err != nil || year < 1 || year > 9999 {
+			http.Error(w, "Invalid year parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+	calendar, err := database.GetVisitCalendar(user.ID, viewerID, year, loc)
+	if err != nil {
+		http.Error(w, "Failed to get visit calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(calendar)
+}
+
+// GetUserProgress ユーザーのメニューの制覇状況を取得するハンドラー
+// 呼び出し元から見える食事記録だけを数える
+func GetUserProgress(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+
+	progress, err := database.GetUserProgress(user, viewerID)
+	if err != nil {
+		http.Error(w, "Failed to get progress", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(progress)
+}
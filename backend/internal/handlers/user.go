@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/middleware"
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
 
 	"github.com/gorilla/mux"
 )
@@ -81,3 +86,178 @@ func GetUserEmails(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(emailMap)
 }
+
+// GetUserRecommendations ユーザーへのおすすめメニューを取得（本人のみ）
+// おすすめは非公開の食事記録や評価からも計算するため、本人以外には返さない
+func GetUserRecommendations(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	recommendations, err := database.GetRecommendations(user.ID, parseLimit(r, 10))
+	if err != nil {
+		http.Error(w, "Failed to get recommendations", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
+
+// requireUser URLパラメータのユーザーIDからユーザーを取得
+// IDが不正またはユーザーが存在しない場合はエラーレスポンスを書き込んでfalseを返す
+func requireUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	user, err := database.GetUserByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "User not found", http.StatusNotFound)
+		} else {
+			http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	return user, true
+}
+
+// requireCaller 呼び出し元のユーザーを取得
+// 呼び出し元が指定されていないか存在しない場合はエラーレスポンスを書き込んでfalseを返す
+func requireCaller(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	callerID, ok := middleware.CurrentUserID(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	user, err := database.GetUserByID(callerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			http.Error(w, "Authentication required", http.StatusUnauthorized)
+		} else {
+			http.Error(w, "Failed to get user", http.StatusInternalServerError)
+		}
+		return nil, false
+	}
+
+	return user, true
+}
+
+// requireSelf URLパラメータのユーザーを取得し、呼び出し元本人か確認
+// 本人以外の場合はエラーレスポンスを書き込んでfalseを返す
+func requireSelf(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return nil, false
+	}
+	caller, ok := requireCaller(w, r)
+	if !ok {
+		return nil, false
+	}
+	if caller.ID != user.ID {
+		http.Error(w, "Cannot access another user's data", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+// userLocation 集計に使うタイムゾーンを取得
+// クエリパラメータtzがあればそれを、なければユーザーのタイムゾーンを使う
+func userLocation(r *http.Request, user *models.User) (*time.Location, error) {
+	if r.URL.Query().Get("tz") != "" {
+		return parseLocation(r)
+	}
+	return userTimeZone(user), nil
+}
+
+// userTimeZone ユーザーのタイムゾーンを取得（未設定や不正な場合はデフォルト）
+func userTimeZone(user *models.User) *time.Location {
+	if user.TimeZone != "" {
+		if loc, err := time.LoadLocation(user.TimeZone); err == nil {
+			return loc
+		}
+	}
+	return period.DefaultLocation()
+}
+
+// UpdateUserTimeZone ユーザーのタイムゾーンを更新（本人のみ）
+func UpdateUserTimeZone(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTimeZoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if _, err := time.LoadLocation(req.TimeZone); err != nil || req.TimeZone == "" {
+		http.Error(w, "Invalid time zone", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.UpdateUserTimeZone(user.ID, req.TimeZone); err != nil {
+		http.Error(w, "Failed to update time zone", http.StatusInternalServerError)
+		return
+	}
+	user.TimeZone = req.TimeZone
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// GetUserSettings ユーザー設定を取得（本人のみ）
+func GetUserSettings(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := database.GetUserSettings(user.ID)
+	if err != nil {
+		http.Error(w, "Failed to get settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateUserSettings ユーザー設定を更新（本人のみ、指定された項目だけを更新する）
+func UpdateUserSettings(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSelf(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.MailLanguage != nil {
+		switch *req.MailLanguage {
+		case "", mailer.LanguageJapanese, mailer.LanguageEnglish:
+		default:
+			http.Error(w, "Invalid mail language", http.StatusBadRequest)
+			return
+		}
+	}
+
+	settings, err := database.UpdateUserSettings(user.ID, req)
+	if err != nil {
+		http.Error(w, "Failed to update settings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gachimatsu-backend/internal/cards"
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/middleware"
+	"gachimatsu-backend/internal/models"
+
+	"github.com/gorilla/mux"
+)
+
+// GetUserYearInReview ユーザーの1年間の活動のまとめを取得するハンドラー
+// 呼び出し元から見える食事記録だけを集計する
+func GetUserYearInReview(w http.ResponseWriter, r *http.Request) {
+	review, ok := loadYearInReview(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// GetUserYearInReviewCard ユーザーの1年間の活動のまとめを共有用のSVG画像で取得するハンドラー
+// 言語はlang、なければAccept-Languageで決める
+func GetUserYearInReviewCard(w http.ResponseWriter, r *http.Request) {
+	review, ok := loadYearInReview(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Write(cards.YearInReviewSVG(review, cardLanguage(r)))
+}
+
+// loadYearInReview パスの{id}と{year}から1年のまとめを取得
+// 取得できない場合はエラーレスポンスを書き込んでfalseを返す
+func loadYearInReview(w http.ResponseWriter, r *http.Request) (*models.YearInReview, bool) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	loc, err := userLocation(r, user)
+	if err != nil {
+		http.Error(w, "Invalid tz parameter", http.StatusBadRequest)
+		return nil, false
+	}
+
+	year, err := strconv.Atoi(mux.Vars(r)["year"])
+	if err != nil || year < 1 || year > time.Now().In(loc).Year() {
+		http.Error(w, "Invalid year", http.StatusBadRequest)
+		return nil, false
+	}
+
+	viewerID, _ := middleware.CurrentUserID(r.Context())
+	review, err := database.GetYearInReview(user, viewerID, year, loc)
+	if err != nil {
+		http.Error(w, "Failed to get year in review", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	return review, true
+}
+
+// cardLanguage 共有用画像の言語をクエリパラメータlangかAccept-Languageから決める
+func cardLanguage(r *http.Request) string {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		return lang
+	}
+	return r.Header.Get("Accept-Language")
+}
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/models"
+	"gachimatsu-backend/internal/period"
+)
+
+// SendWeeklyDigests 週間サマリーを受け取る設定のユーザーに前の週のまとめを送信
+// 同じ週のまとめは1回だけ送信し、活動がなかった週は送信しない
+// 送信したユーザー数を返す
+func SendWeeklyDigests(now time.Time) (int, error) {
+	recipients, err := database.GetWeeklyDigestRecipients()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range recipients {
+		ok, err := deliverWeeklyDigest(&recipients[i], now)
+		if err != nil {
+			log.Printf("Failed to send weekly digest to user %d: %v", recipients[i].User.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, nil
+}
+
+// deliverWeeklyDigest ユーザーの前の週のまとめを通知とメールで送信
+// 送信の記録・通知・メールは1つのトランザクションで作成し、途中で失敗した場合は次回の実行で再送する
+func deliverWeeklyDigest(recipient *models.WeeklyDigestRecipient, now time.Time) (bool, error) {
+	user := &recipient.User
+	loc, err := period.LoadLocation(user.TimeZone)
+	if err != nil {
+		loc = period.DefaultLocation()
+	}
+
+	digest, err := database.GetWeeklyDigest(user.ID, database.PreviousWeek(now, loc))
+	if err != nil {
+		return false, err
+	}
+	if digest.IsEmpty() {
+		return false, nil
+	}
+
+	notification := &models.Notification{
+		UserID:    user.ID,
+		Type:      models.NotificationWeeklyDigest,
+		Message:   fmt.Sprintf("%s〜%sの週間サマリー：来店%d回、支出%d円", digest.WeekStart, digest.WeekEnd, digest.Visits, digest.Spend),
+		DedupeKey: "weekly_digest:" + digest.WeekStart,
+	}
+
+	// 確認済みのメールアドレスにだけメールを送る
+	var email *models.QueuedEmail
+	if user.EmailVerifiedAt != nil {
+		lang := recipient.MailLanguage
+		if lang == "" {
+			lang = mailer.DefaultLanguage()
+		}
+		msg, err := mailer.Render("weekly_digest", lang, user.Email, digest)
+		if err != nil {
+			return false, err
+		}
+		email = &models.QueuedEmail{To: msg.To, Subject: msg.Subject, Body: msg.Body}
+	}
+
+	return database.RecordWeeklyDigest(user.ID, digest.WeekStart, notification, email)
+}
@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/models"
+)
+
+// emailBatchSize 1回のジョブで送信するメールの最大件数
+const emailBatchSize = 50
+
+// emailRetryBaseDelay 再送までの待ち時間の初期値（失敗するたびに2倍にする）
+const emailRetryBaseDelay = time.Minute
+
+// emailRetryMaxDelay 再送までの待ち時間の上限
+const emailRetryMaxDelay = time.Hour
+
+// emailMaxAttempts メールの送信を試みる最大回数を環境変数から取得（デフォルト: 5回）
+func emailMaxAttempts() int {
+	attempts, err := strconv.Atoi(os.Getenv("MAIL_MAX_ATTEMPTS"))
+	if err != nil || attempts <= 0 {
+		attempts = 5
+	}
+	return attempts
+}
+
+// emailRetryDelay attempts回失敗した後の再送までの待ち時間
+func emailRetryDelay(attempts int) time.Duration {
+	delay := emailRetryBaseDelay
+	for i := 1; i < attempts && delay < emailRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > emailRetryMaxDelay {
+		delay = emailRetryMaxDelay
+	}
+	return delay
+}
+
+// emailQueue 送信待ちのメールを保持するキュー
+type emailQueue interface {
+	GetDueEmails(limit int) ([]models.QueuedEmail, error)
+	MarkEmailSent(id int) error
+	MarkEmailFailed(id int, sendErr error, nextAttemptAt *time.Time) error
+}
+
+// databaseEmailQueue データベースのemail_queueテーブルを使うキュー
+type databaseEmailQueue struct{}
+
+func (databaseEmailQueue) GetDueEmails(limit int) ([]models.QueuedEmail, error) {
+	return database.GetDueEmails(limit)
+}
+
+func (databaseEmailQueue) MarkEmailSent(id int) error {
+	return database.MarkEmailSent(id)
+}
+
+func (databaseEmailQueue) MarkEmailFailed(id int, sendErr error, nextAttemptAt *time.Time) error {
+	return database.MarkEmailFailed(id, sendErr, nextAttemptAt)
+}
+
+// DeliverQueuedEmails 送信待ちのメールをmで送信し、送信できた件数を返す
+// 送信に失敗したメールは待ち時間を延ばしながら再送し、最大回数に達したら失敗として確定する
+func DeliverQueuedEmails(m mailer.Mailer) (int, error) {
+	return deliverQueuedEmails(databaseEmailQueue{}, m)
+}
+
+// deliverQueuedEmails queueの送信待ちのメールをmで送信し、送信できた件数を返す
+func deliverQueuedEmails(queue emailQueue, m mailer.Mailer) (int, error) {
+	emails, err := queue.GetDueEmails(emailBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	maxAttempts := emailMaxAttempts()
+	sent := 0
+	for _, email := range emails {
+		sendErr := m.Send(mailer.Message{To: email.To, Subject: email.Subject, Body: email.Body})
+		if sendErr == nil {
+			if err := queue.MarkEmailSent(email.ID); err != nil {
+				return sent, err
+			}
+			sent++
+			continue
+		}
+
+		attempts := email.Attempts + 1
+		var nextAttemptAt *time.Time
+		if attempts < maxAttempts {
+			next := time.Now().Add(emailRetryDelay(attempts))
+			nextAttemptAt = &next
+			log.Printf("Failed to send email %d to %s (attempt %d): %v", email.ID, email.To, attempts, sendErr)
+		} else {
+			log.Printf("Giving up sending email %d to %s after %d attempts: %v", email.ID, email.To, attempts, sendErr)
+		}
+		if err := queue.MarkEmailFailed(email.ID, sendErr, nextAttemptAt); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
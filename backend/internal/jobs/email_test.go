@@ -0,0 +1,179 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/mailer/smtptest"
+	"gachimatsu-backend/internal/models"
+)
+
+// memoryEmail メモリ上のキューに保存されたメール
+type memoryEmail struct {
+	models.QueuedEmail
+	status        string
+	lastError     string
+	nextAttemptAt *time.Time
+}
+
+// memoryEmailQueue テスト用のメモリ上のキュー
+// 再送までの待ち時間は無視し、送信待ちのメールをすべて返す
+type memoryEmailQueue struct {
+	emails []*memoryEmail
+}
+
+func (q *memoryEmailQueue) add(to, subject, body string) *memoryEmail {
+	email := &memoryEmail{
+		QueuedEmail: models.QueuedEmail{ID: len(q.emails) + 1, To: to, Subject: subject, Body: body},
+		status:      models.EmailStatusPending,
+	}
+	q.emails = append(q.emails, email)
+	return email
+}
+
+func (q *memoryEmailQueue) GetDueEmails(limit int) ([]models.QueuedEmail, error) {
+	var due []models.QueuedEmail
+	for _, email := range q.emails {
+		if email.status == models.EmailStatusPending && len(due) < limit {
+			due = append(due, email.QueuedEmail)
+		}
+	}
+	return due, nil
+}
+
+func (q *memoryEmailQueue) MarkEmailSent(id int) error {
+	email := q.emails[id-1]
+	email.status = models.EmailStatusSent
+	email.Attempts++
+	email.lastError = ""
+	return nil
+}
+
+func (q *memoryEmailQueue) MarkEmailFailed(id int, sendErr error, nextAttemptAt *time.Time) error {
+	email := q.emails[id-1]
+	if nextAttemptAt == nil {
+		email.status = models.EmailStatusFailed
+	}
+	email.Attempts++
+	email.lastError = sendErr.Error()
+	email.nextAttemptAt = nextAttemptAt
+	return nil
+}
+
+// newTestMailer smtptestのサーバーに送信するMailerを作成
+func newTestMailer(t *testing.T) (*smtptest.Server, mailer.Mailer) {
+	t.Helper()
+	server, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start SMTP server: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	host, port := server.HostPort()
+	return server, &mailer.SMTPMailer{Host: host, Port: port, From: "noreply@gachimatsu.local"}
+}
+
+func TestDeliverQueuedEmails(t *testing.T) {
+	server, m := newTestMailer(t)
+	queue := &memoryEmailQueue{}
+	first := queue.add("first@example.com", "first", "body")
+	second := queue.add("second@example.com", "second", "body")
+
+	sent, err := deliverQueuedEmails(queue, m)
+	if err != nil {
+		t.Fatalf("deliverQueuedEmails() error = %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("sent = %d, want 2", sent)
+	}
+	for _, email := range []*memoryEmail{first, second} {
+		if email.status != models.EmailStatusSent || email.Attempts != 1 {
+			t.Errorf("email %d: status = %s, attempts = %d", email.ID, email.status, email.Attempts)
+		}
+	}
+	if n := len(server.Messages()); n != 2 {
+		t.Errorf("server received %d messages, want 2", n)
+	}
+}
+
+func TestDeliverQueuedEmailsRetriesUntilSent(t *testing.T) {
+	t.Setenv("MAIL_MAX_ATTEMPTS", "3")
+	server, m := newTestMailer(t)
+	queue := &memoryEmailQueue{}
+	email := queue.add("user@example.com", "subject", "body")
+	server.FailNext(1)
+
+	before := time.Now()
+	if sent, err := deliverQueuedEmails(queue, m); err != nil || sent != 0 {
+		t.Fatalf("first attempt: sent = %d, err = %v", sent, err)
+	}
+	if email.status != models.EmailStatusPending || email.Attempts != 1 {
+		t.Fatalf("after first attempt: status = %s, attempts = %d", email.status, email.Attempts)
+	}
+	if email.lastError == "" {
+		t.Error("last error is not recorded")
+	}
+	if email.nextAttemptAt == nil || email.nextAttemptAt.Before(before.Add(emailRetryBaseDelay)) {
+		t.Errorf("next attempt = %v, want at least %v later", email.nextAttemptAt, emailRetryBaseDelay)
+	}
+
+	if sent, err := deliverQueuedEmails(queue, m); err != nil || sent != 1 {
+		t.Fatalf("second attempt: sent = %d, err = %v", sent, err)
+	}
+	if email.status != models.EmailStatusSent || email.Attempts != 2 {
+		t.Errorf("after second attempt: status = %s, attempts = %d", email.status, email.Attempts)
+	}
+	if n := len(server.Messages()); n != 1 {
+		t.Errorf("server received %d messages, want 1", n)
+	}
+}
+
+func TestDeliverQueuedEmailsGivesUpAfterMaxAttempts(t *testing.T) {
+	t.Setenv("MAIL_MAX_ATTEMPTS", "3")
+	server, m := newTestMailer(t)
+	queue := &memoryEmailQueue{}
+	email := queue.add("user@example.com", "subject", "body")
+	server.FailNext(10)
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		if _, err := deliverQueuedEmails(queue, m); err != nil {
+			t.Fatalf("attempt %d: error = %v", attempt, err)
+		}
+		if email.Attempts != attempt {
+			t.Fatalf("attempt %d: attempts = %d", attempt, email.Attempts)
+		}
+		if attempt < 3 && (email.status != models.EmailStatusPending || email.nextAttemptAt == nil) {
+			t.Fatalf("attempt %d: status = %s, next attempt = %v, want retry", attempt, email.status, email.nextAttemptAt)
+		}
+	}
+	if email.status != models.EmailStatusFailed || email.nextAttemptAt != nil {
+		t.Errorf("status = %s, next attempt = %v, want failed without retry", email.status, email.nextAttemptAt)
+	}
+
+	// 失敗として確定したメールは再送しない
+	if sent, err := deliverQueuedEmails(queue, m); err != nil || sent != 0 || email.Attempts != 3 {
+		t.Errorf("after giving up: sent = %d, attempts = %d, err = %v", sent, email.Attempts, err)
+	}
+	if n := len(server.Messages()); n != 0 {
+		t.Errorf("server received %d messages, want 0", n)
+	}
+}
+
+func TestEmailRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{7, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := emailRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("emailRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"gachimatsu-backend/internal/database"
+	"gachimatsu-backend/internal/mailer"
+	"gachimatsu-backend/internal/period"
+)
+
+// Start 定期実行するジョブをすべて開始
+func Start() {
+	loc := period.DefaultLocation()
+
+	// ランキングのスナップショットを起動時と毎日0時5分に保存
+	RunNow("ranking-snapshot", saveRankingSnapshot)
+	Daily("ranking-snapshot", 0, 5, loc, saveRankingSnapshot)
+
+	// メニューの類似度を起動時と一定間隔で再計算
+	RunNow("menu-similarity", database.RefreshMenuSimilarities)
+	Every("menu-similarity", similarityRefreshInterval(), database.RefreshMenuSimilarities)
+
+	// チャレンジの開始・終了を参加者に通知
+	RunNow("challenge-notifications", notifyChallengeEvents)
+	Every("challenge-notifications", challengeNotificationInterval, notifyChallengeEvents)
+
+	// 前の週のまとめを毎週月曜日の8時に送信
+	Weekly("weekly-digest", time.Monday, 8, 0, loc, sendWeeklyDigests)
+
+	// 送信待ちのメールを一定間隔で送信
+	m, err := mailer.New()
+	if err != nil {
+		log.Printf("Email delivery disabled: %v", err)
+		return
+	}
+	deliver := func() error {
+		_, err := DeliverQueuedEmails(m)
+		return err
+	}
+	Every("email-queue", emailQueueInterval, deliver)
+}
+
+// emailQueueInterval 送信待ちのメールを確認する間隔
+const emailQueueInterval = 30 * time.Second
+
+// challengeNotificationInterval チャレンジの開始・終了を確認する間隔
+const challengeNotificationInterval = 5 * time.Minute
+
+// similarityRefreshInterval メニュー類似度の再計算間隔を環境変数から取得（デフォルト: 60分）
+func similarityRefreshInterval() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("SIMILARITY_REFRESH_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 60
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// saveRankingSnapshot 今日の日付でランキングのスナップショットを保存
+func saveRankingSnapshot() error {
+	return database.SaveRankingSnapshot(time.Now().In(period.DefaultLocation()))
+}
+
+// notifyChallengeEvents 開始・終了したチャレンジを参加者に通知
+func notifyChallengeEvents() error {
+	_, err := database.NotifyChallengeEvents()
+	return err
+}
+
+// sendWeeklyDigests 前の週のまとめを送信
+func sendWeeklyDigests() error {
+	_, err := SendWeeklyDigests(time.Now())
+	return err
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// Daily 毎日指定時刻（locのタイムゾーン）にジョブを実行するゴルーチンを開始
+func Daily(name string, hour, minute int, loc *time.Location, job func() error) {
+	go func() {
+		for {
+			now := time.Now().In(loc)
+			next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, loc)
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			time.Sleep(time.Until(next))
+			run(name, job)
+		}
+	}()
+}
+
+// Every 一定間隔でジョブを実行するゴルーチンを開始
+func Every(name string, interval time.Duration, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run(name, job)
+		}
+	}()
+}
+
+// RunNow ジョブをすぐに1回実行するゴルーチンを開始
+func RunNow(name string, job func() error) {
+	go run(name, job)
+}
+
+// run ジョブを実行し、結果をログに記録
+func run(name string, job func() error) {
+	start := time.Now()
+	if err := job(); err != nil {
+		log.Printf("Job %s failed: %v", name, err)
+		return
+	}
+	log.Printf("Job %s completed in %v", name, time.Since(start))
+}
+
+// Weekly 毎週指定曜日の指定時刻（locのタイムゾーン）にジョブを実行するゴルーチンを開始
+func Weekly(name string, weekday time.Weekday, hour, minute int, loc *time.Location, job func() error) {
+	go func() {
+		for {
+			now := time.Now().In(loc)
+			days := (int(weekday) - int(now.Weekday()) + 7) % 7
+			next := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, loc)
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 7)
+			}
+			time.Sleep(time.Until(next))
+			run(name, job)
+		}
+	}()
+}
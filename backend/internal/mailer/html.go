@@ -0,0 +1,30 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+)
+
+// htmlTemplates HTMLで表示するテンプレート（templates/<名前>.<言語>.html）
+// 各テンプレートは<名前>.<言語>.htmlという名前で定義する
+//
+//go:embed templates/*.html
+var htmlTemplateFS embed.FS
+
+var htmlTemplates = template.Must(template.New("").ParseFS(htmlTemplateFS, "templates/*.html"))
+
+// RenderHTML テンプレートからHTMLを作成
+// nameはテンプレート名、langは言語（対応していない場合は日本語）
+func RenderHTML(name, lang string, data interface{}) (string, error) {
+	templateName := fmt.Sprintf("%s.%s.html", name, NormalizeLanguage(lang))
+	if htmlTemplates.Lookup(templateName) == nil {
+		return "", fmt.Errorf("html template %q not found", templateName)
+	}
+	var buf bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&buf, templateName, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
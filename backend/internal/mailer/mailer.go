@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"os"
+	"strings"
+	"time"
+)
+
+// Message 送信するメールを表す構造体
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer メールを送信するインターフェース
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New 環境変数MAIL_DRIVERに応じたMailerを作成
+// smtp: SMTPサーバーで送信、outbox（デフォルト）: 開発用にファイルへ書き出す
+func New() (Mailer, error) {
+	from := getEnv("MAIL_FROM", "noreply@gachimatsu.local")
+
+	switch driver := getEnv("MAIL_DRIVER", "outbox"); driver {
+	case "smtp":
+		return &SMTPMailer{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnv("SMTP_PORT", "25"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	case "outbox":
+		return &OutboxMailer{
+			Dir:  getEnv("MAIL_OUTBOX_DIR", "outbox"),
+			From: from,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver: %s", driver)
+	}
+}
+
+// getEnv 環境変数を取得（デフォルト値付き）
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// formatMessage メールをRFC 5322形式に変換
+// 件名はMIMEエンコードし、本文はUTF-8のquoted-printableで書き出す
+func formatMessage(from string, msg Message, now time.Time) ([]byte, error) {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(from, "\r\n") {
+		return nil, fmt.Errorf("invalid address")
+	}
+
+	var buf bytes.Buffer
+	headers := []struct{ name, value string }{
+		{"From", from},
+		{"To", msg.To},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", messageID(from)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header.name, header.value)
+	}
+	buf.WriteString("\r\n")
+
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\n", "\r\n")
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\r\n")
+
+	return buf.Bytes(), nil
+}
+
+// messageID Message-IDヘッダーの値を生成
+func messageID(from string) string {
+	domain := "localhost"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = strings.Trim(from[i+1:], "> ")
+	}
+	random := make([]byte, 12)
+	rand.Read(random)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// OutboxMailer 開発用にメールを送信せずファイルへ書き出すMailer
+// Dirに1通ずつ.emlファイルを作成し、ログに出力する
+type OutboxMailer struct {
+	Dir  string
+	From string
+}
+
+// Send メールをファイルへ書き出す
+func (m *OutboxMailer) Send(msg Message) error {
+	now := time.Now()
+	data, err := formatMessage(m.From, msg, now)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.eml", now.Format("20060102-150405"), now.UnixNano())
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	log.Printf("Mail to %s written to %s: %s", msg.To, path, msg.Subject)
+	return nil
+}
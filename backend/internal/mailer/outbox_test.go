@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutboxMailerSend(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	m := &OutboxMailer{Dir: dir, From: "noreply@gachimatsu.local"}
+
+	messages := []Message{
+		{To: "user@example.com", Subject: "メールアドレスの確認", Body: testBody},
+		{To: "other@example.com", Subject: "Weekly digest", Body: "Hello\n"},
+	}
+	for _, msg := range messages {
+		if err := m.Send(msg); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != len(messages) {
+		t.Fatalf("outbox has %d .eml files, want %d", len(paths), len(messages))
+	}
+
+	// ファイル名は書き出した時刻順に並ぶ
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkMessage(t, string(data), m.From, messages[i])
+	}
+}
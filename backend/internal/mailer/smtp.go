@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer SMTPサーバー経由でメールを送信するMailer
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Send メールを送信
+// Usernameが設定されている場合はPLAIN認証を使う（サーバーが対応していればSTARTTLSで暗号化する）
+func (m *SMTPMailer) Send(msg Message) error {
+	data, err := formatMessage(m.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	return smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, from.Address, []string{to.Address}, data)
+}
@@ -0,0 +1,121 @@
+package mailer
+
+import (
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"gachimatsu-backend/internal/mailer/smtptest"
+)
+
+// testBody 日本語と、quoted-printableで折り返される長い行を含む本文
+var testBody = "牛めし並盛を注文しました。\n" + strings.Repeat("a", 100) + "\n= で始まる行\n"
+
+// parseMessage 送信されたメールを解析し、ヘッダーとデコードした本文を返す
+func parseMessage(t *testing.T, data string) (mail.Header, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to parse message: %v", err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	return msg.Header, string(body)
+}
+
+// checkMessage ヘッダーと本文が送信したメールと一致するか確認
+func checkMessage(t *testing.T, data, from string, want Message) {
+	t.Helper()
+	header, body := parseMessage(t, data)
+
+	if got := header.Get("From"); got != from {
+		t.Errorf("From = %q, want %q", got, from)
+	}
+	if got := header.Get("To"); got != want.To {
+		t.Errorf("To = %q, want %q", got, want.To)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("failed to decode subject: %v", err)
+	}
+	if subject != want.Subject {
+		t.Errorf("Subject = %q, want %q", subject, want.Subject)
+	}
+	if got := header.Get("Content-Type"); got != "text/plain; charset=UTF-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+		t.Errorf("Content-Transfer-Encoding = %q", got)
+	}
+	if header.Get("Date") == "" {
+		t.Error("Date header is missing")
+	}
+	if !strings.HasSuffix(header.Get("Message-ID"), "@gachimatsu.local>") {
+		t.Errorf("Message-ID = %q", header.Get("Message-ID"))
+	}
+
+	// 末尾の改行はSMTPのDATAの終端と区別できないため比較しない
+	body = strings.TrimRight(body, "\r\n")
+	wantBody := strings.TrimRight(strings.ReplaceAll(want.Body, "\n", "\r\n"), "\r\n")
+	if body != wantBody {
+		t.Errorf("body = %q, want %q", body, wantBody)
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start SMTP server: %v", err)
+	}
+	defer server.Close()
+
+	host, port := server.HostPort()
+	m := &SMTPMailer{Host: host, Port: port, From: "Gachimatsu <noreply@gachimatsu.local>"}
+	msg := Message{To: "user@example.com", Subject: "今週のまとめ", Body: testBody}
+	if err := m.Send(msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	received := messages[0]
+	if received.From != "noreply@gachimatsu.local" {
+		t.Errorf("envelope from = %q", received.From)
+	}
+	if len(received.To) != 1 || received.To[0] != "user@example.com" {
+		t.Errorf("envelope to = %q", received.To)
+	}
+	checkMessage(t, received.Data, m.From, msg)
+}
+
+func TestSMTPMailerSendRejected(t *testing.T) {
+	server, err := smtptest.NewServer()
+	if err != nil {
+		t.Fatalf("failed to start SMTP server: %v", err)
+	}
+	defer server.Close()
+	server.FailNext(1)
+
+	host, port := server.HostPort()
+	m := &SMTPMailer{Host: host, Port: port, From: "noreply@gachimatsu.local"}
+	if err := m.Send(Message{To: "user@example.com", Subject: "test", Body: "test"}); err == nil {
+		t.Fatal("Send() error = nil, want temporary failure")
+	}
+	if n := len(server.Messages()); n != 0 {
+		t.Errorf("server received %d messages, want 0", n)
+	}
+}
+
+func TestFormatMessageRejectsHeaderInjection(t *testing.T) {
+	msg := Message{To: "user@example.com\r\nBcc: other@example.com", Subject: "test", Body: "test"}
+	if err := (&SMTPMailer{From: "noreply@gachimatsu.local"}).Send(msg); err == nil {
+		t.Fatal("Send() error = nil, want invalid address")
+	}
+}
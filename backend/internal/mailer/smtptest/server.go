@@ -0,0 +1,188 @@
+// Package smtptest メール送信の動作確認に使うプロセス内の簡易SMTPサーバー
+// 受け取ったメールを保持するだけで、実際には配送しない
+package smtptest
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Message サーバーが受け取ったメールを表す構造体
+type Message struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Server プロセス内の簡易SMTPサーバー
+type Server struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []Message
+	// failures 残りの受信を一時エラー（451）で拒否する回数
+	failures int
+
+	wg sync.WaitGroup
+}
+
+// NewServer 127.0.0.1の空いているポートでサーバーを起動
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: listener}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr サーバーのアドレス（host:port）
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// HostPort サーバーのホストとポート
+func (s *Server) HostPort() (string, string) {
+	host, port, _ := net.SplitHostPort(s.Addr())
+	return host, port
+}
+
+// Messages これまでに受け取ったメール
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
+
+// FailNext 次のn通の受信を一時エラーで拒否する（再送の確認用）
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = n
+}
+
+// Close サーバーを停止
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+// serve 接続を受け付ける
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle 1つの接続でSMTPのやり取りを行う
+func (s *Server) handle(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+
+	reply("220 smtptest ESMTP ready")
+	var current Message
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-smtptest")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "HELO"):
+			reply("250 smtptest")
+		case strings.HasPrefix(command, "AUTH"):
+			reply("235 Authentication successful")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			current = Message{From: extractAddress(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			current.To = append(current.To, extractAddress(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			data, err := readData(reader)
+			if err != nil {
+				return
+			}
+			current.Data = data
+			if s.accept(current) {
+				reply("250 OK: queued")
+			} else {
+				reply("451 Temporary failure")
+			}
+			current = Message{}
+		case command == "RSET":
+			current = Message{}
+			reply("250 OK")
+		case command == "NOOP":
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// accept 受け取ったメールを保存（拒否する場合はfalseを返す）
+func (s *Server) accept(msg Message) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return false
+	}
+	s.messages = append(s.messages, msg)
+	return true
+}
+
+// readData DATAコマンドの本文を終端（.のみの行）まで読み込む
+func readData(reader *bufio.Reader) (string, error) {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "." {
+			return strings.Join(lines, "\r\n"), nil
+		}
+		// ドットで始まる行は送信側で.が追加されている
+		lines = append(lines, strings.TrimPrefix(line, "."))
+	}
+}
+
+// extractAddress <address> 形式からアドレスを取り出す
+func extractAddress(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, " "); i >= 0 {
+		value = value[:i]
+	}
+	return strings.Trim(value, "<>")
+}
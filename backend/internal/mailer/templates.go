@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// 対応している言語
+const (
+	LanguageJapanese = "ja"
+	LanguageEnglish  = "en"
+)
+
+// templateFS メールのテンプレート（templates/<名前>.<言語>.tmpl）
+// 各テンプレートはsubjectとbodyの2つを定義する
+//
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").ParseFS(templateFS, "templates/*.tmpl"))
+
+// DefaultLanguage 環境変数MAIL_DEFAULT_LANGUAGEからメールの言語を取得（デフォルト: ja）
+func DefaultLanguage() string {
+	return NormalizeLanguage(getEnv("MAIL_DEFAULT_LANGUAGE", LanguageJapanese))
+}
+
+// NormalizeLanguage 言語の指定を対応している言語に変換（en以外は日本語）
+func NormalizeLanguage(lang string) string {
+	if strings.HasPrefix(strings.ToLower(lang), LanguageEnglish) {
+		return LanguageEnglish
+	}
+	return LanguageJapanese
+}
+
+// Render テンプレートからメールを作成
+// nameはテンプレート名、langは言語（対応していない場合は日本語）
+func Render(name, lang, to string, data interface{}) (Message, error) {
+	lang = NormalizeLanguage(lang)
+	subject, err := execute(fmt.Sprintf("%s.%s.subject", name, lang), data)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := execute(fmt.Sprintf("%s.%s.body", name, lang), data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject),
+		Body:    strings.TrimLeft(body, "\n"),
+	}, nil
+}
+
+// execute 名前付きテンプレートを実行
+func execute(name string, data interface{}) (string, error) {
+	if templates.Lookup(name) == nil {
+		return "", fmt.Errorf("mail template %q not found", name)
+	}
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
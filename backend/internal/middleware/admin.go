@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+)
+
+// AdminAuth 管理APIの認証middleware
+// X-Admin-Tokenヘッダーが環境変数ADMIN_TOKENと一致する場合のみ通過させる
+// ADMIN_TOKENが未設定の場合は管理APIをすべて拒否する
+func AdminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		adminToken := os.Getenv("ADMIN_TOKEN")
+		token := r.Header.Get("X-Admin-Token")
+		if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -10,7 +10,7 @@ func CORS(next http.Handler) http.Handler {
 		// フロントエンドのURLを許可（本番環境では適切に設定する）
 		w.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Token")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		// プリフライトリクエストの処理
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"gachimatsu-backend/internal/auth"
+	"gachimatsu-backend/internal/database"
+)
+
+type contextKey string
+
+const currentUserKey contextKey = "current_user_id"
+
+// CurrentUser リクエストしたユーザーをコンテキストに設定するmiddleware
+// Authorization: Bearer ヘッダーのセッショントークンで呼び出し元を特定する（指定されていない場合は匿名）
+// セッショントークンが無効な場合は401を返す
+func CurrentUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token, ok := BearerToken(r); ok {
+			id, err := database.GetSessionUserID(auth.HashSessionToken(token))
+			if err != nil {
+				if err == sql.ErrNoRows {
+					http.Error(w, "Invalid or expired session", http.StatusUnauthorized)
+				} else {
+					http.Error(w, "Failed to verify session", http.StatusInternalServerError)
+				}
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), currentUserKey, id))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// BearerToken Authorizationヘッダーからセッショントークンを取得
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// CurrentUserID コンテキストから呼び出し元のユーザーIDを取得
+func CurrentUserID(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(currentUserKey).(int)
+	return id, ok
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Achievement 実績（バッジ）と獲得状況を表す構造体
+type Achievement struct {
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Unlocked    bool       `json:"unlocked"`
+	UnlockedAt  *time.Time `json:"unlocked_at"`
+	Progress    int        `json:"progress"`
+	Target      int        `json:"target"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// LoginRequest ログイン時のリクエスト構造体
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// LoginResponse ログイン成功時のレスポンス構造体
+// tokenはAuthorization: Bearer ヘッダーに指定して使う
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      *User     `json:"user"`
+}
+
+// EmailRequest メールアドレスを指定するリクエスト構造体（確認メール・再設定メールの送信）
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest メールアドレス確認時のリクエスト構造体
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ResetPasswordRequest パスワード再設定時のリクエスト構造体
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
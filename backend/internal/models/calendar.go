@@ -0,0 +1,23 @@
+package models
+
+// CalendarDay ある日の来店回数を表す構造体
+type CalendarDay struct {
+	Date   string `json:"date"`
+	Visits int    `json:"visits"`
+}
+
+// VisitCalendar 1年間の来店カレンダーと連続記録を表す構造体
+type VisitCalendar struct {
+	UserID   int    `json:"user_id"`
+	Year     int    `json:"year"`
+	TimeZone string `json:"time_zone"`
+	// Days その年の全日付の来店回数（来店の無い日は0）
+	Days           []CalendarDay `json:"days"`
+	TotalVisitDays int           `json:"total_visit_days"`
+	// CurrentDayStreak 今日（今日まだ来店していなければ昨日）まで続いている連続来店日数
+	CurrentDayStreak int `json:"current_day_streak"`
+	LongestDayStreak int `json:"longest_day_streak"`
+	// CurrentWeekStreak 今週（今週まだ来店していなければ先週）まで続いている連続来店週数
+	CurrentWeekStreak int `json:"current_week_streak"`
+	LongestWeekStreak int `json:"longest_week_streak"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// チャレンジの達成条件
+const (
+	// ChallengeRuleAll 対象メニューをすべて食べる
+	ChallengeRuleAll = "all"
+	// ChallengeRuleCount 対象メニューを指定された種類以上食べる
+	ChallengeRuleCount = "count"
+)
+
+// Challenge 期間限定チャレンジを表す構造体
+type Challenge struct {
+	ID          int       `json:"id" db:"id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	StartsAt    time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt      time.Time `json:"ends_at" db:"ends_at"`
+	Rule        string    `json:"rule" db:"rule"`
+	// RequiredCount 達成に必要な対象メニューの種類数（ruleがcountの場合のみ）
+	RequiredCount    *int      `json:"required_count,omitempty" db:"required_count"`
+	TargetMenuIDs    []int     `json:"target_menu_ids"`
+	TargetCategories []string  `json:"target_categories"`
+	ParticipantCount int       `json:"participant_count"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateChallengeRequest チャレンジ作成時のリクエスト構造体
+type CreateChallengeRequest struct {
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	Rule          string    `json:"rule"`
+	RequiredCount *int      `json:"required_count"`
+	MenuIDs       []int     `json:"menu_ids"`
+	Categories    []string  `json:"categories"`
+}
+
+// ChallengeProgress チャレンジ参加者の進捗を表す構造体
+type ChallengeProgress struct {
+	ChallengeID int       `json:"challenge_id"`
+	UserID      int       `json:"user_id"`
+	UserName    string    `json:"user_name"`
+	JoinedAt    time.Time `json:"joined_at"`
+	// EatenMenuIDs 期間中に食べた対象メニュー
+	EatenMenuIDs []int `json:"eaten_menu_ids"`
+	Progress     int   `json:"progress"`
+	Required     int   `json:"required"`
+	Completed    bool  `json:"completed"`
+	// CompletedAt 達成条件を満たした注文の日時
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// ChallengeLeaderboardEntry チャレンジのリーダーボードの1行を表す構造体
+type ChallengeLeaderboardEntry struct {
+	Rank int `json:"rank"`
+	ChallengeProgress
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// OrderComment 食事記録へのコメントを表す構造体
+type OrderComment struct {
+	ID        int        `json:"id" db:"id"`
+	OrderID   int        `json:"order_id" db:"order_id"`
+	UserID    int        `json:"user_id" db:"user_id"`
+	UserName  string     `json:"user_name"`
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CommentRequest コメントの投稿・編集時のリクエスト構造体
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+// ReactionCount 絵文字ごとのリアクション数を表す構造体
+type ReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
+	// ReactedByMe 呼び出し元がこの絵文字でリアクションしているか
+	ReactedByMe bool `json:"reacted_by_me"`
+}
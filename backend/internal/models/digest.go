@@ -0,0 +1,52 @@
+package models
+
+// WeeklyDigest ユーザーの1週間の活動のまとめを表す構造体
+type WeeklyDigest struct {
+	UserID   int    `json:"user_id"`
+	UserName string `json:"user_name"`
+	// WeekStart 週の初日（月曜日、YYYY-MM-DD）
+	WeekStart string `json:"week_start"`
+	// WeekEnd 週の最終日（日曜日、YYYY-MM-DD）
+	WeekEnd string `json:"week_end"`
+	Visits  int    `json:"visits"`
+	Spend   int    `json:"spend"`
+	// PreviousWeekSpend 前の週の支出
+	PreviousWeekSpend int `json:"previous_week_spend"`
+	// NewMenus この週に初めて食べたメニュー
+	NewMenus []DigestMenu `json:"new_menus"`
+	// RatingCount この週に評価したメニューの数
+	RatingCount int `json:"rating_count"`
+	// FavoriteRankChanges よく食べるメニューの人気ランキングでの順位の変化
+	FavoriteRankChanges []FavoriteRankChange `json:"favorite_rank_changes"`
+}
+
+// DigestMenu まとめに載せるメニューを表す構造体
+type DigestMenu struct {
+	MenuID   int    `json:"menu_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// FavoriteRankChange よく食べるメニューの週初めと週末の順位を表す構造体
+type FavoriteRankChange struct {
+	MenuID int    `json:"menu_id"`
+	Name   string `json:"name"`
+	// Rank 週末時点の順位（スナップショットがない場合はnull）
+	Rank *int `json:"rank"`
+	// PreviousRank 週初め時点の順位（スナップショットがない場合はnull）
+	PreviousRank *int `json:"previous_rank"`
+	// RankChange 順位の変化（up/down/same/new）
+	RankChange string `json:"rank_change"`
+}
+
+// WeeklyDigestRecipient 週間サマリーを受け取るユーザーを表す構造体
+type WeeklyDigestRecipient struct {
+	User User
+	// MailLanguage メールの言語（空の場合はデフォルトの言語）
+	MailLanguage string
+}
+
+// IsEmpty この週に来店も評価もしていない場合はtrue
+func (d *WeeklyDigest) IsEmpty() bool {
+	return d.Visits == 0 && d.RatingCount == 0
+}
@@ -0,0 +1,17 @@
+package models
+
+// メールキューの状態
+const (
+	EmailStatusPending = "pending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// QueuedEmail 送信待ちのメールを表す構造体
+type QueuedEmail struct {
+	ID       int    `json:"id" db:"id"`
+	To       string `json:"to" db:"to_address"`
+	Subject  string `json:"subject" db:"subject"`
+	Body     string `json:"body" db:"body"`
+	Attempts int    `json:"attempts" db:"attempts"`
+}
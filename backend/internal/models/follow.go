@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// 食事記録と評価の公開範囲
+const (
+	// VisibilityPublic 全員に公開
+	VisibilityPublic = "public"
+	// VisibilityFollowers フォロワーにのみ公開
+	VisibilityFollowers = "followers"
+	// VisibilityPrivate 本人のみ
+	VisibilityPrivate = "private"
+)
+
+// IsValidVisibility 公開範囲が有効か判定
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPublic, VisibilityFollowers, VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// FollowRequest フォロー時のリクエスト構造体
+type FollowRequest struct {
+	UserID int `json:"user_id"`
+}
+
+// FollowUser フォロー・フォロワー一覧のユーザーを表す構造体
+type FollowUser struct {
+	UserID     int       `json:"user_id"`
+	Name       string    `json:"name"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// フィードの項目の種類
+const (
+	FeedKindOrder       = "order"
+	FeedKindRating      = "rating"
+	FeedKindAchievement = "achievement"
+)
+
+// FeedItem アクティビティフィードの項目を表す構造体
+type FeedItem struct {
+	Kind       string    `json:"kind"`
+	ID         int       `json:"id,omitempty"`
+	UserID     int       `json:"user_id"`
+	UserName   string    `json:"user_name"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// 食事記録・評価の場合のメニュー情報
+	MenuID       int    `json:"menu_id,omitempty"`
+	MenuName     string `json:"menu_name,omitempty"`
+	MenuCategory string `json:"menu_category,omitempty"`
+	Quantity     int    `json:"quantity,omitempty"`
+	Rating       int    `json:"rating,omitempty"`
+	// 実績の場合の実績情報
+	AchievementCode string `json:"achievement_code,omitempty"`
+	AchievementName string `json:"achievement_name,omitempty"`
+}
+
+// FeedPage アクティビティフィードの1ページを表す構造体
+type FeedPage struct {
+	Items []FeedItem `json:"items"`
+	// NextCursor 次のページを取得するカーソル（最後のページの場合は空）
+	NextCursor string `json:"next_cursor"`
+}
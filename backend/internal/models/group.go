@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// グループ内の役割
+const (
+	GroupRoleOwner  = "owner"
+	GroupRoleMember = "member"
+)
+
+// Group ユーザーグループを表す構造体
+type Group struct {
+	ID   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	// InviteCode 招待コード（メンバーにのみ返す）
+	InviteCode  string    `json:"invite_code,omitempty" db:"invite_code"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateGroupRequest グループ作成時のリクエスト構造体
+type CreateGroupRequest struct {
+	Name string `json:"name"`
+}
+
+// JoinGroupRequest 招待コードでグループに参加する際のリクエスト構造体
+type JoinGroupRequest struct {
+	InviteCode string `json:"invite_code"`
+}
+
+// GroupMember グループのメンバーを表す構造体
+type GroupMember struct {
+	UserID   int       `json:"user_id"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// GroupMenuProgress グループでのメニューの制覇状況を表す構造体
+type GroupMenuProgress struct {
+	MenuID   int    `json:"menu_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	// EatenBy このメニューを食べたことのあるメンバー
+	EatenBy []int `json:"eaten_by"`
+}
+
+// GroupCategoryProgress グループでのカテゴリの制覇状況を表す構造体
+type GroupCategoryProgress struct {
+	Category  string `json:"category"`
+	Conquered int    `json:"conquered"`
+	Total     int    `json:"total"`
+}
+
+// GroupProgress グループ全体の制覇状況（メンバーが食べたメニューの和集合）を表す構造体
+type GroupProgress struct {
+	GroupID    int                     `json:"group_id"`
+	Conquered  int                     `json:"conquered"`
+	Total      int                     `json:"total"`
+	Categories []GroupCategoryProgress `json:"categories"`
+	Menus      []GroupMenuProgress     `json:"menus"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// リーダーボードの種類
+const (
+	// LeaderboardConquests 食べたことのあるメニューの種類数
+	LeaderboardConquests = "conquests"
+	// LeaderboardVisits 来店回数
+	LeaderboardVisits = "visits"
+	// LeaderboardRatings 評価したメニューの数
+	LeaderboardRatings = "ratings"
+)
+
+// LeaderboardEntry リーダーボードの1行を表す構造体
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	UserID   int    `json:"user_id"`
+	UserName string `json:"user_name"`
+	Value    int    `json:"value"`
+	// ReachedAt 現在の値に到達した日時（同じ値の場合は早い方が上位）
+	ReachedAt time.Time `json:"reached_at"`
+}
+
+// Leaderboard ユーザーリーダーボードを表す構造体
+type Leaderboard struct {
+	Type    string             `json:"type"`
+	From    *time.Time         `json:"from,omitempty"`
+	To      *time.Time         `json:"to,omitempty"`
+	Entries []LeaderboardEntry `json:"entries"`
+	// Me 呼び出し元ユーザーの順位（上位に入っていなくても返す）
+	Me *LeaderboardEntry `json:"me"`
+}
+
+// UserSettings ユーザー設定を表す構造体
+type UserSettings struct {
+	UserID            int  `json:"user_id" db:"user_id"`
+	LeaderboardOptOut bool `json:"leaderboard_opt_out" db:"leaderboard_opt_out"`
+	// WeeklyDigest 週間サマリーを受け取るか
+	WeeklyDigest bool `json:"weekly_digest" db:"weekly_digest"`
+	// PublicBadge 制覇状況のバッジを誰でも表示できるようにするか
+	PublicBadge bool `json:"public_badge" db:"public_badge"`
+	// MailLanguage メールの言語（ja・en、空の場合はデフォルトの言語）
+	MailLanguage string `json:"mail_language" db:"mail_language"`
+}
+
+// UpdateUserSettingsRequest ユーザー設定更新時のリクエスト構造体
+// 指定された項目だけを更新する
+type UpdateUserSettingsRequest struct {
+	LeaderboardOptOut *bool   `json:"leaderboard_opt_out"`
+	WeeklyDigest      *bool   `json:"weekly_digest"`
+	PublicBadge       *bool   `json:"public_badge"`
+	MailLanguage      *string `json:"mail_language"`
+}
@@ -13,4 +13,25 @@ type Menu struct {
 	IsAvailable bool      `json:"is_available" db:"is_available"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
-} 
\ No newline at end of file
+}
+
+// UpdateMenuRequest メニュー更新時のリクエスト構造体（指定した項目のみ更新する）
+type UpdateMenuRequest struct {
+	Name        *string `json:"name"`
+	Category    *string `json:"category"`
+	Price       *int    `json:"price"`
+	Description *string `json:"description"`
+	ImageURL    *string `json:"image_url"`
+	IsAvailable *bool   `json:"is_available"`
+}
+
+// PriceChange メニューの価格変更を表す構造体
+type PriceChange struct {
+	ID        int       `json:"id" db:"id"`
+	MenuID    int       `json:"menu_id" db:"menu_id"`
+	MenuName  string    `json:"menu_name,omitempty" db:"name"`
+	Category  string    `json:"category,omitempty" db:"category"`
+	OldPrice  int       `json:"old_price" db:"old_price"`
+	NewPrice  int       `json:"new_price" db:"new_price"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// 通知の種類
+const (
+	NotificationComment        = "comment"
+	NotificationReaction       = "reaction"
+	NotificationAchievement    = "achievement"
+	NotificationFollow         = "follow"
+	NotificationChallengeStart = "challenge_start"
+	NotificationChallengeEnd   = "challenge_end"
+	NotificationBudgetWarning  = "budget_warning"
+	NotificationWeeklyDigest   = "weekly_digest"
+)
+
+// NotificationTypes 通知の種類の一覧（受信設定の表示順）
+var NotificationTypes = []string{
+	NotificationAchievement,
+	NotificationFollow,
+	NotificationComment,
+	NotificationReaction,
+	NotificationChallengeStart,
+	NotificationChallengeEnd,
+	NotificationBudgetWarning,
+	NotificationWeeklyDigest,
+}
+
+// IsValidNotificationType 通知の種類が有効か判定
+func IsValidNotificationType(kind string) bool {
+	for _, t := range NotificationTypes {
+		if t == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification ユーザーへの通知を表す構造体
+type Notification struct {
+	ID      int    `json:"id" db:"id"`
+	UserID  int    `json:"user_id" db:"user_id"`
+	Type    string `json:"type" db:"type"`
+	ActorID *int   `json:"actor_id" db:"actor_id"`
+	OrderID *int   `json:"order_id" db:"order_id"`
+	Message string `json:"message" db:"message"`
+	// DedupeKey 同じ出来事の通知を重複させないためのキー（指定した場合のみ）
+	DedupeKey string `json:"-" db:"dedupe_key"`
+	// ReadAt 既読にした日時（未読の場合はnull）
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// UnreadCount 未読の通知数を表す構造体
+type UnreadCount struct {
+	UnreadCount int `json:"unread_count"`
+}
+
+// NotificationPreference 通知の種類ごとの受信設定を表す構造体
+type NotificationPreference struct {
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
+
+// UpdateNotificationPreferencesRequest 通知の受信設定の更新リクエスト構造体
+// 指定された種類の設定だけを更新する
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
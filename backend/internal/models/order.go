@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Order 食事記録（注文）を表す構造体
+// 単価・メニュー名・カテゴリは注文時点のメニュー情報を保持する
+type Order struct {
+	ID           int       `json:"id" db:"id"`
+	UserID       int       `json:"user_id" db:"user_id"`
+	MenuID       int       `json:"menu_id" db:"menu_id"`
+	Quantity     int       `json:"quantity" db:"quantity"`
+	UnitPrice    int       `json:"unit_price" db:"unit_price"`
+	MenuName     string    `json:"menu_name" db:"menu_name"`
+	MenuCategory string    `json:"menu_category" db:"menu_category"`
+	OrderDate    time.Time `json:"order_date" db:"order_date"`
+	Visibility   string    `json:"visibility" db:"visibility"`
+	// CommentCount コメント数
+	CommentCount int `json:"comment_count"`
+	// Reactions 絵文字ごとのリアクション数
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+// CreateOrderRequest 食事記録の作成リクエストを表す構造体
+type CreateOrderRequest struct {
+	MenuID   int `json:"menu_id"`
+	Quantity int `json:"quantity"`
+	// OrderDate 食事した日時（省略時は現在時刻）
+	OrderDate *time.Time `json:"order_date"`
+	// Visibility 公開範囲（public/followers/private、省略時はpublic）
+	Visibility string `json:"visibility"`
+}
@@ -0,0 +1,19 @@
+package models
+
+// CategoryProgress カテゴリごとのメニューの制覇状況を表す構造体
+type CategoryProgress struct {
+	Category  string `json:"category"`
+	Conquered int    `json:"conquered"`
+	Total     int    `json:"total"`
+}
+
+// UserProgress ユーザーのメニューの制覇状況を表す構造体
+type UserProgress struct {
+	UserID    int    `json:"user_id"`
+	UserName  string `json:"user_name"`
+	Conquered int    `json:"conquered"`
+	Total     int    `json:"total"`
+	// Percentage 制覇率（0〜100、小数点以下1桁）
+	Percentage float64            `json:"percentage"`
+	Categories []CategoryProgress `json:"categories"`
+}
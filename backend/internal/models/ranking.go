@@ -1,5 +1,7 @@
 package models
 
+import "gachimatsu-backend/internal/period"
+
 // PopularMenu 人気メニューの構造体
 type PopularMenu struct {
 	MenuID      int     `json:"menu_id" db:"menu_id"`
@@ -9,6 +11,12 @@ type PopularMenu struct {
 	OrderCount  int     `json:"order_count" db:"order_count"`
 	AvgRating   float64 `json:"avg_rating" db:"avg_rating"`
 	TotalRating int     `json:"total_rating" db:"total_rating"`
+	Score       float64 `json:"score" db:"score"`
+	Rank        int     `json:"rank"`
+	// PreviousRank 前日のスナップショットでの順位（スナップショットに無い場合はnil）
+	PreviousRank *int `json:"previous_rank,omitempty"`
+	// RankChange 前日からの順位変動（up/down/same/new）
+	RankChange string `json:"rank_change,omitempty"`
 }
 
 // MenuRanking メニューランキングのレスポンス構造体
@@ -16,3 +24,67 @@ type MenuRanking struct {
 	OverallRanking   []PopularMenu            `json:"overall_ranking"`
 	CategoryRankings map[string][]PopularMenu `json:"category_rankings"`
 }
+
+// TrendingMenu 時間減衰を適用したトレンドメニューの構造体
+type TrendingMenu struct {
+	PopularMenu
+	DecayedOrders float64 `json:"decayed_orders" db:"decayed_orders"`
+	DecayedRating float64 `json:"decayed_rating" db:"decayed_rating"`
+	TrendScore    float64 `json:"trend_score" db:"trend_score"`
+}
+
+// ランキングスコアの計算方法
+const (
+	// ScoringWeighted 注文数と評価合計の加重和（従来の計算方法）
+	ScoringWeighted = "weighted"
+	// ScoringBayesian 事前分布で補正した評価のベイズ平均
+	ScoringBayesian = "bayesian"
+	// ScoringWilson 高評価（4以上）の割合のWilsonスコア区間の下限
+	ScoringWilson = "wilson"
+)
+
+// ScoringOptions ランキングスコアの計算方法とパラメータを表す構造体
+type ScoringOptions struct {
+	Method       string
+	OrderWeight  float64
+	RatingWeight float64
+	// PriorMean ベイズ平均の事前平均（nilの場合は全評価の平均）
+	PriorMean *float64
+	// PriorWeight ベイズ平均の事前分布の重み（仮想的な評価件数）
+	PriorWeight float64
+	// WilsonZ Wilsonスコア区間の信頼水準に対応するz値
+	WilsonZ float64
+}
+
+// RankingQuery ランキングの取得条件を表す構造体
+type RankingQuery struct {
+	Limit   int
+	Period  period.Range
+	Scoring ScoringOptions
+	// GroupID 指定した場合はグループのメンバーの注文と評価だけで集計する
+	GroupID int
+	// ViewerID グループで集計する場合に、このユーザーから見える注文と評価だけを対象にする
+	ViewerID int
+}
+
+// 前日からの順位変動
+const (
+	RankChangeUp   = "up"
+	RankChangeDown = "down"
+	RankChangeSame = "same"
+	RankChangeNew  = "new"
+)
+
+// RankSnapshot ある日のランキングスナップショットでの順位を表す構造体
+type RankSnapshot struct {
+	Date  string  `json:"date" db:"snapshot_date"`
+	Rank  int     `json:"rank" db:"menu_rank"`
+	Score float64 `json:"score" db:"score"`
+}
+
+// MenuRankHistory メニューの順位の推移を表す構造体
+type MenuRankHistory struct {
+	MenuID  int            `json:"menu_id"`
+	Scope   string         `json:"scope"`
+	History []RankSnapshot `json:"history"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// MenuRating メニューの評価を表す構造体
+type MenuRating struct {
+	ID         int       `json:"id" db:"id"`
+	UserID     int       `json:"user_id" db:"user_id"`
+	MenuID     int       `json:"menu_id" db:"menu_id"`
+	Rating     int       `json:"rating" db:"rating"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	Visibility string    `json:"visibility" db:"visibility"`
+}
+
+// RateMenuRequest メニュー評価時のリクエスト構造体
+type RateMenuRequest struct {
+	MenuID int `json:"menu_id"`
+	Rating int `json:"rating"`
+	// Visibility 公開範囲（public/followers/private、省略時はpublic）
+	Visibility string `json:"visibility"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// 推薦の根拠の種類
+const (
+	// RecommendationCollaborative 似た好みのユーザーの注文・評価に基づく推薦
+	RecommendationCollaborative = "collaborative"
+	// RecommendationCategory よく食べるカテゴリの未制覇メニューの推薦
+	RecommendationCategory = "category"
+	// RecommendationPopular 人気メニューランキングに基づく推薦
+	RecommendationPopular = "popular"
+)
+
+// Recommendation ユーザーへのおすすめメニューを表す構造体
+type Recommendation struct {
+	MenuID   int     `json:"menu_id"`
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Price    int     `json:"price"`
+	Score    float64 `json:"score"`
+	Source   string  `json:"source"`
+	Reason   string  `json:"reason"`
+	// BasedOnMenuIDs 推薦の根拠となったユーザーの既食メニュー
+	BasedOnMenuIDs []int `json:"based_on_menu_ids,omitempty"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// 共有リンクの対象
+const (
+	ShareTargetOrder    = "order"
+	ShareTargetProgress = "progress"
+)
+
+// IsValidShareTarget 共有リンクの対象として正しいかどうかを返す
+func IsValidShareTarget(target string) bool {
+	return target == ShareTargetOrder || target == ShareTargetProgress
+}
+
+// ShareLink 食事記録・制覇状況の公開共有リンクを表す構造体
+type ShareLink struct {
+	ID     int    `json:"id" db:"id"`
+	UserID int    `json:"user_id" db:"user_id"`
+	Target string `json:"target" db:"target"`
+	// OrderID 共有する食事記録（targetがorderの場合のみ）
+	OrderID *int `json:"order_id" db:"order_id"`
+	// Token 共有リンクのトークン（ログインせずに閲覧できる）
+	Token string `json:"token"`
+	// URL 共有するページのURL
+	URL string `json:"url"`
+	// ImageURL OGP画像のURL
+	ImageURL string `json:"image_url"`
+	// ExpiresAt 有効期限（期限なしの場合はnull）
+	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateShareLinkRequest 共有リンク作成時のリクエスト構造体
+type CreateShareLinkRequest struct {
+	Target  string `json:"target"`
+	OrderID *int   `json:"order_id"`
+	// ExpiresInDays 有効期間の日数（省略時は期限なし）
+	ExpiresInDays *int `json:"expires_in_days"`
+}
+
+// SharedOrder 共有リンクで公開する食事記録を表す構造体
+type SharedOrder struct {
+	MenuID       int       `json:"menu_id"`
+	MenuName     string    `json:"menu_name"`
+	MenuCategory string    `json:"menu_category"`
+	MenuImageURL string    `json:"menu_image_url"`
+	Quantity     int       `json:"quantity"`
+	OrderDate    time.Time `json:"order_date"`
+}
+
+// SharedContent 共有リンクで公開する内容を表す構造体
+type SharedContent struct {
+	Target   string `json:"target"`
+	UserName string `json:"user_name"`
+	// Order 共有された食事記録（targetがorderの場合のみ）
+	Order *SharedOrder `json:"order,omitempty"`
+	// Progress 共有したユーザーの制覇状況
+	Progress *UserProgress `json:"progress"`
+	// ImageURL OGP画像のURL
+	ImageURL string `json:"image_url"`
+}
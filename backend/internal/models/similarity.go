@@ -0,0 +1,26 @@
+package models
+
+// SimilarMenu 類似メニューを表す構造体
+type SimilarMenu struct {
+	MenuID   int     `json:"menu_id" db:"similar_menu_id"`
+	Name     string  `json:"name" db:"name"`
+	Category string  `json:"category" db:"category"`
+	Price    int     `json:"price" db:"price"`
+	Score    float64 `json:"score" db:"score"`
+	// CoOrderScore 両方を注文したユーザーの割合（Jaccard係数）
+	CoOrderScore float64 `json:"co_order_score" db:"co_order_score"`
+	// CategoryScore 同じカテゴリなら1
+	CategoryScore float64 `json:"category_score" db:"category_score"`
+	// PriceScore 価格の近さ（同じ価格なら1）
+	PriceScore float64 `json:"price_score" db:"price_score"`
+	// RatingScore 両方を評価したユーザーの評価の相関を0〜1に変換した値
+	RatingScore float64 `json:"rating_score" db:"rating_score"`
+}
+
+// SimilarityWeights 類似度の各要素の重みを表す構造体
+type SimilarityWeights struct {
+	CoOrder  float64
+	Category float64
+	Price    float64
+	Rating   float64
+}
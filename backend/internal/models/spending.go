@@ -0,0 +1,37 @@
+package models
+
+// SpendingPoint 期間ごとの支出を表す構造体
+type SpendingPoint struct {
+	PeriodStart string `json:"period_start"`
+	Amount      int    `json:"amount"`
+	Visits      int    `json:"visits"`
+	Orders      int    `json:"orders"`
+}
+
+// SpendingSeries 支出の推移を表す構造体
+type SpendingSeries struct {
+	UserID   int             `json:"user_id"`
+	Interval string          `json:"interval"`
+	Total    int             `json:"total"`
+	Points   []SpendingPoint `json:"points"`
+}
+
+// BudgetRequest 月間予算の設定リクエストを表す構造体
+type BudgetRequest struct {
+	MonthlyBudget int `json:"monthly_budget"`
+}
+
+// BudgetStatus 月間予算に対する支出状況を表す構造体
+type BudgetStatus struct {
+	UserID int    `json:"user_id"`
+	Month  string `json:"month"`
+	// MonthlyBudget 月間予算（未設定の場合はnil）
+	MonthlyBudget *int `json:"monthly_budget"`
+	Spent         int  `json:"spent"`
+	// Remaining 予算の残り（未設定の場合はnil、超過した場合は負の値）
+	Remaining *int `json:"remaining"`
+	// ProjectedSpend 現在のペースで支出した場合の月末時点の支出見込み
+	ProjectedSpend int `json:"projected_spend"`
+	// Warning 支出見込みが予算を超える場合はtrue
+	Warning bool `json:"warning"`
+}
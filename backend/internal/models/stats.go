@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Stats 統計情報を表す構造体
 type Stats struct {
 	TotalMenus     int                `json:"total_menus"`
@@ -13,3 +15,24 @@ type CategoryStats struct {
 	Category string `json:"category" db:"category"`
 	Count    int    `json:"count" db:"count"`
 }
+
+// PriceChangeReport 指定日以降のメニュー価格変更のレポートを表す構造体
+type PriceChangeReport struct {
+	Since time.Time `json:"since"`
+	// AveragePrice 現在の全メニューの平均価格
+	AveragePrice float64 `json:"average_price"`
+	// AveragePriceAtSince 指定日時点の全メニューの平均価格
+	AveragePriceAtSince float64 `json:"average_price_at_since"`
+	// AverageDrift 指定日時点からのメニューあたりの平均価格変動
+	AverageDrift  float64                       `json:"average_drift"`
+	CategoryDrift map[string]CategoryPriceDrift `json:"category_drift"`
+	Changes       []PriceChange                 `json:"changes"`
+}
+
+// CategoryPriceDrift カテゴリごとの価格変動を表す構造体
+type CategoryPriceDrift struct {
+	AveragePrice        float64 `json:"average_price"`
+	AveragePriceAtSince float64 `json:"average_price_at_since"`
+	AverageDrift        float64 `json:"average_drift"`
+	ChangedMenus        int     `json:"changed_menus"`
+}
@@ -4,9 +4,17 @@ import "time"
 
 // User ユーザーを表す構造体
 type User struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	TimeZone string `json:"time_zone"`
+	// EmailVerifiedAt メールアドレスを確認した日時（未確認の場合はnull）
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// UpdateTimeZoneRequest タイムゾーン変更時のリクエスト構造体
+type UpdateTimeZoneRequest struct {
+	TimeZone string `json:"time_zone"`
+}
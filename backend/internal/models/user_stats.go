@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// 時間帯の区分
+const (
+	TimeOfDayMorning   = "morning"    // 5時〜10時
+	TimeOfDayLunch     = "lunch"      // 10時〜15時
+	TimeOfDayAfternoon = "afternoon"  // 15時〜17時
+	TimeOfDayDinner    = "dinner"     // 17時〜22時
+	TimeOfDayLateNight = "late_night" // 22時〜5時
+)
+
+// UserStats ユーザーごとの統計情報を表す構造体
+type UserStats struct {
+	UserID               int               `json:"user_id"`
+	TotalVisits          int               `json:"total_visits"`
+	TotalOrders          int               `json:"total_orders"`
+	TotalSpent           int               `json:"total_spent"`
+	AverageSpendPerVisit float64           `json:"average_spend_per_visit"`
+	FavoriteMenu         *FavoriteMenu     `json:"favorite_menu"`
+	FavoriteCategory     *FavoriteCategory `json:"favorite_category"`
+	// MostFrequentWeekday 最も来店の多い曜日（Sunday〜Saturday）
+	MostFrequentWeekday string `json:"most_frequent_weekday"`
+	// MostFrequentTimeOfDay 最も来店の多い時間帯（morning/lunch/afternoon/dinner/late_night）
+	MostFrequentTimeOfDay string     `json:"most_frequent_time_of_day"`
+	LongestGap            *VisitGap  `json:"longest_gap"`
+	FirstVisitAt          *time.Time `json:"first_visit_at"`
+	LastVisitAt           *time.Time `json:"last_visit_at"`
+}
+
+// FavoriteMenu 最もよく注文したメニューを表す構造体
+type FavoriteMenu struct {
+	MenuID int    `json:"menu_id"`
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+}
+
+// FavoriteCategory 最もよく注文したカテゴリを表す構造体
+type FavoriteCategory struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// VisitGap 来店の間隔を表す構造体
+type VisitGap struct {
+	Days int       `json:"days"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
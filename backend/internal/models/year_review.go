@@ -0,0 +1,29 @@
+package models
+
+// YearInReview ユーザーの1年間の松屋での活動のまとめを表す構造体
+type YearInReview struct {
+	UserID   int    `json:"user_id"`
+	UserName string `json:"user_name"`
+	Year     int    `json:"year"`
+	TimeZone string `json:"time_zone"`
+	// TotalVisits その年の来店回数
+	TotalVisits int `json:"total_visits"`
+	// TotalSpend その年の支出
+	TotalSpend int `json:"total_spend"`
+	// TopMenus その年によく注文したメニュー（注文数の多い順）
+	TopMenus []FavoriteMenu `json:"top_menus"`
+	// TopCategory その年に最もよく注文したカテゴリ（注文がない場合はnull）
+	TopCategory *FavoriteCategory `json:"top_category"`
+	// MostCommonTimeOfDay 最も来店の多い時間帯（morning/lunch/afternoon/dinner/late_night）
+	MostCommonTimeOfDay string `json:"most_common_time_of_day"`
+	// LongestStreak その年の最長の連続来店日数
+	LongestStreak int `json:"longest_streak"`
+	// MenusEaten その年に食べたメニューの種類数
+	MenusEaten int `json:"menus_eaten"`
+	// MenusConquered その年に初めて食べたメニューの数
+	MenusConquered int `json:"menus_conquered"`
+	// Percentile その年に来店した他のユーザーのうち来店回数が自分より少ない人の割合（0〜100、閲覧者から見える記録だけで比べる）
+	Percentile float64 `json:"percentile"`
+	// ActiveUsers その年に来店したユーザーの数
+	ActiveUsers int `json:"active_users"`
+}
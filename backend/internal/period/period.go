@@ -0,0 +1,99 @@
+package period
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// 集計期間の種類
+const (
+	Daily   = "daily"
+	Weekly  = "weekly"
+	Monthly = "monthly"
+)
+
+// DateLayout 日付パラメータの形式
+const DateLayout = "2006-01-02"
+
+// defaultTimeZone タイムゾーンのデフォルト値
+const defaultTimeZone = "Asia/Tokyo"
+
+// Range 集計期間を表す構造体（Fromを含みToを含まない半開区間）
+// FromまたはToがゼロ値の場合はその側に制限がないことを表す
+type Range struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsZero 期間の制限がないかどうかを返す
+func (r Range) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// DefaultLocation 集計に使うデフォルトのタイムゾーンを環境変数APP_TIME_ZONEから取得
+func DefaultLocation() *time.Location {
+	name := os.Getenv("APP_TIME_ZONE")
+	if name == "" {
+		name = defaultTimeZone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		// tzdataが無い環境でも日本時間で動作させる
+		return time.FixedZone("JST", 9*60*60)
+	}
+	return loc
+}
+
+// LoadLocation タイムゾーン名からロケーションを取得（空文字の場合はデフォルト）
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return DefaultLocation(), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// ParseDate YYYY-MM-DD形式の日付を指定タイムゾーンの0時として解析
+func ParseDate(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, value, loc)
+}
+
+// Day tを含む1日の期間を取得
+func Day(t time.Time, loc *time.Location) Range {
+	t = t.In(loc)
+	from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return Range{From: from, To: from.AddDate(0, 0, 1)}
+}
+
+// Week tを含むISO週（月曜始まり）の期間を取得
+func Week(t time.Time, loc *time.Location) Range {
+	day := Day(t, loc)
+	offset := (int(day.From.Weekday()) + 6) % 7
+	from := day.From.AddDate(0, 0, -offset)
+	return Range{From: from, To: from.AddDate(0, 0, 7)}
+}
+
+// Month tを含む暦月の期間を取得
+func Month(t time.Time, loc *time.Location) Range {
+	t = t.In(loc)
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	return Range{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// Of 期間の種類に応じてanchorを含む期間を取得
+func Of(kind string, anchor time.Time, loc *time.Location) (Range, error) {
+	switch kind {
+	case Daily:
+		return Day(anchor, loc), nil
+	case Weekly:
+		return Week(anchor, loc), nil
+	case Monthly:
+		return Month(anchor, loc), nil
+	default:
+		return Range{}, fmt.Errorf("unknown period %q", kind)
+	}
+}
@@ -0,0 +1,12 @@
+-- ランキングスナップショットテーブル
+-- scope は全体ランキングが 'overall'、カテゴリ別ランキングが 'category:<カテゴリ名>'
+CREATE TABLE IF NOT EXISTS ranking_snapshots (
+    snapshot_date DATE NOT NULL,
+    scope VARCHAR(120) NOT NULL,
+    menu_id INT NOT NULL,
+    menu_rank INT NOT NULL,
+    score DOUBLE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (snapshot_date, scope, menu_id),
+    INDEX idx_ranking_snapshots_menu (menu_id, scope, snapshot_date)
+);
@@ -0,0 +1,13 @@
+-- メニュー類似度テーブル（バックグラウンドジョブで定期的に再計算する）
+CREATE TABLE IF NOT EXISTS menu_similarities (
+    menu_id INT NOT NULL,
+    similar_menu_id INT NOT NULL,
+    score DOUBLE NOT NULL,
+    co_order_score DOUBLE NOT NULL,
+    category_score DOUBLE NOT NULL,
+    price_score DOUBLE NOT NULL,
+    rating_score DOUBLE NOT NULL,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (menu_id, similar_menu_id),
+    INDEX idx_menu_similarities_score (menu_id, score)
+);
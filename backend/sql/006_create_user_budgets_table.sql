@@ -0,0 +1,7 @@
+-- ユーザーの月間予算テーブル
+CREATE TABLE IF NOT EXISTS user_budgets (
+    user_id INT PRIMARY KEY,
+    monthly_budget INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
@@ -0,0 +1,14 @@
+-- 注文時点のメニュー情報（単価・メニュー名・カテゴリ）を保持する列を追加
+ALTER TABLE orders
+    ADD COLUMN unit_price INT NULL AFTER quantity,
+    ADD COLUMN menu_name VARCHAR(100) NULL AFTER unit_price,
+    ADD COLUMN menu_category VARCHAR(50) NULL AFTER menu_name;
+
+-- 既存の注文は現在のメニュー情報で埋める
+UPDATE orders o
+JOIN menus m ON m.id = o.menu_id
+SET
+    o.unit_price = m.price,
+    o.menu_name = m.name,
+    o.menu_category = m.category
+WHERE o.unit_price IS NULL;
@@ -0,0 +1,10 @@
+-- メニュー価格履歴テーブル（管理APIで価格が変更されたときに記録する）
+CREATE TABLE IF NOT EXISTS menu_price_history (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    menu_id INT NOT NULL,
+    old_price INT NOT NULL,
+    new_price INT NOT NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_menu_price_history_menu (menu_id, changed_at),
+    INDEX idx_menu_price_history_changed_at (changed_at)
+);
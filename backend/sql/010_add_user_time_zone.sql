@@ -0,0 +1,3 @@
+-- ユーザーごとのタイムゾーン（カレンダーや連続記録の集計に使う）
+ALTER TABLE users
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT 'Asia/Tokyo' AFTER email;
@@ -0,0 +1,7 @@
+-- ユーザーの実績（バッジ）獲得テーブル
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id INT NOT NULL,
+    achievement_code VARCHAR(120) NOT NULL,
+    unlocked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, achievement_code)
+);
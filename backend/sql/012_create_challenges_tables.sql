@@ -0,0 +1,30 @@
+-- 期間限定チャレンジテーブル
+-- rule は対象メニューをすべて食べる 'all' か、required_count 種類以上食べる 'count'
+CREATE TABLE IF NOT EXISTS challenges (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(100) NOT NULL,
+    description TEXT,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    rule VARCHAR(20) NOT NULL DEFAULT 'all',
+    required_count INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- チャレンジの対象（メニューまたはカテゴリ）
+CREATE TABLE IF NOT EXISTS challenge_targets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    challenge_id INT NOT NULL,
+    menu_id INT NULL,
+    category VARCHAR(50) NULL,
+    INDEX idx_challenge_targets_challenge (challenge_id)
+);
+
+-- チャレンジの参加者
+CREATE TABLE IF NOT EXISTS challenge_participants (
+    challenge_id INT NOT NULL,
+    user_id INT NOT NULL,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (challenge_id, user_id),
+    INDEX idx_challenge_participants_user (user_id)
+);
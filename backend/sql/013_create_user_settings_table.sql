@@ -0,0 +1,7 @@
+-- ユーザーごとの設定テーブル
+-- leaderboard_opt_out が TRUE のユーザーはリーダーボードに表示しない
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id INT PRIMARY KEY,
+    leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
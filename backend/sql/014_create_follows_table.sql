@@ -0,0 +1,15 @@
+-- ユーザー間のフォロー関係テーブル
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INT NOT NULL,
+    followee_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    INDEX idx_follows_followee (followee_id)
+);
+
+-- 食事記録と評価の公開範囲（public: 全員、followers: フォロワーのみ、private: 本人のみ）
+ALTER TABLE orders
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public';
+
+ALTER TABLE menu_ratings
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public';
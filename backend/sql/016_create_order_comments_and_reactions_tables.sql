@@ -0,0 +1,32 @@
+-- 食事記録へのコメントテーブル
+CREATE TABLE IF NOT EXISTS order_comments (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    order_id INT NOT NULL,
+    user_id INT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    INDEX idx_order_comments_order (order_id, created_at)
+);
+
+-- 食事記録への絵文字リアクションテーブル（同じ絵文字は1人1回まで）
+CREATE TABLE IF NOT EXISTS order_reactions (
+    order_id INT NOT NULL,
+    user_id INT NOT NULL,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, user_id, emoji)
+);
+
+-- ユーザーへの通知テーブル
+CREATE TABLE IF NOT EXISTS notifications (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    actor_id INT NULL,
+    order_id INT NULL,
+    message VARCHAR(255) NOT NULL,
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notifications_user (user_id, created_at)
+);
@@ -0,0 +1,13 @@
+-- 同じ出来事の通知を重複して作らないためのキー（ユーザーごとに一意）
+ALTER TABLE notifications
+    ADD COLUMN dedupe_key VARCHAR(191) NULL AFTER message,
+    ADD UNIQUE KEY uk_notifications_dedupe (user_id, dedupe_key),
+    ADD INDEX idx_notifications_unread (user_id, read_at);
+
+-- 通知の種類ごとの受信設定（行がない種類は受信する）
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id INT NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (user_id, type)
+);
@@ -0,0 +1,15 @@
+-- 送信待ちのメールキュー（送信に失敗した場合は next_attempt_at まで待って再送する）
+-- status は 'pending'（送信待ち）、'sent'（送信済み）、'failed'（再送上限に達した）
+CREATE TABLE IF NOT EXISTS email_queue (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    to_address VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    next_attempt_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP NULL,
+    INDEX idx_email_queue_due (status, next_attempt_at)
+);
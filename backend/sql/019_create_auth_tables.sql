@@ -0,0 +1,38 @@
+-- パスワードとメールアドレスの確認状態
+ALTER TABLE users
+    ADD COLUMN password_hash VARCHAR(255) NULL AFTER email,
+    ADD COLUMN email_verified_at TIMESTAMP NULL AFTER password_hash;
+
+-- ログインセッション（トークンはSHA-256のハッシュだけを保存する）
+CREATE TABLE IF NOT EXISTS sessions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    UNIQUE KEY uk_sessions_token_hash (token_hash),
+    INDEX idx_sessions_user (user_id)
+);
+
+-- メールアドレス確認・パスワード再設定のトークン（1回だけ使える）
+-- purpose は 'verify_email' か 'password_reset'
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    purpose VARCHAR(30) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_tokens_user (user_id, purpose)
+);
+
+-- 確認メール・再設定メールのリクエスト履歴（回数制限に使う）
+-- key_value はメールアドレスまたはIPアドレス
+CREATE TABLE IF NOT EXISTS auth_request_log (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    action VARCHAR(30) NOT NULL,
+    key_value VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_auth_request_log_key (action, key_value, created_at)
+);
@@ -0,0 +1,11 @@
+-- 週間サマリーの受信設定（オプトイン）
+ALTER TABLE user_settings
+    ADD COLUMN weekly_digest BOOLEAN NOT NULL DEFAULT FALSE AFTER leaderboard_opt_out;
+
+-- 週間サマリーの送信履歴（同じ週のサマリーを重複して送らない）
+CREATE TABLE IF NOT EXISTS weekly_digest_log (
+    user_id INT NOT NULL,
+    week_start DATE NOT NULL,
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, week_start)
+);
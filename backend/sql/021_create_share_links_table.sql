@@ -0,0 +1,13 @@
+-- 食事記録・制覇状況の公開共有リンク
+-- target は 'order'（食事記録、order_idを指定）か 'progress'（制覇状況）
+-- リンクのトークンはIDを署名したもので、トークン自体は保存しない
+CREATE TABLE IF NOT EXISTS share_links (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    target VARCHAR(20) NOT NULL,
+    order_id INT NULL,
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_share_links_user (user_id)
+);
@@ -0,0 +1,3 @@
+-- 制覇状況のバッジを公開するか（オプトイン）
+ALTER TABLE user_settings
+    ADD COLUMN public_badge BOOLEAN NOT NULL DEFAULT FALSE AFTER weekly_digest;
@@ -0,0 +1,11 @@
+-- メニュー評価はユーザーとメニューの組み合わせごとに1件にする
+-- 重複している評価は最新のもの（IDが最大のもの）だけを残す
+DELETE older FROM menu_ratings older
+JOIN menu_ratings newer
+    ON newer.user_id = older.user_id
+    AND newer.menu_id = older.menu_id
+    AND newer.id > older.id;
+
+-- created_at は最後に評価した日時（評価し直すと更新される）
+ALTER TABLE menu_ratings
+    ADD UNIQUE KEY uk_menu_ratings_user_menu (user_id, menu_id);
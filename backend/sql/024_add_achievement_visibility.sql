@@ -0,0 +1,4 @@
+-- 実績の公開範囲（獲得に必要な記録の公開範囲で決める）
+-- 既に獲得済みの実績はどの記録に基づくか分からないため本人のみにする
+ALTER TABLE user_achievements
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'private' AFTER unlocked_at;
@@ -0,0 +1,3 @@
+-- メールの言語（ja・en、空の場合は環境変数MAIL_DEFAULT_LANGUAGEの言語）
+ALTER TABLE user_settings
+    ADD COLUMN mail_language VARCHAR(5) NOT NULL DEFAULT '' AFTER public_badge;